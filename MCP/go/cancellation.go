@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// requestIDMetaKey is where the JSON-RPC id of a tools/call is stashed so
+// that the tool middleware, which never sees the id, can find it.
+const requestIDMetaKey = "listenapi/requestId"
+
+// inflightCalls tracks running tool calls and extra method requests so that
+// notifications/cancelled can abort the upstream request they are waiting
+// on.
+type inflightCalls struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newInflightCalls() *inflightCalls {
+	return &inflightCalls{cancels: make(map[string]context.CancelFunc)}
+}
+
+// register wires the tracker into an MCP server.
+func (c *inflightCalls) register(hooks *server.Hooks) []server.ServerOption {
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest) {
+		if message.Params.Meta == nil {
+			message.Params.Meta = &mcp.Meta{}
+		}
+		if message.Params.Meta.AdditionalFields == nil {
+			message.Params.Meta.AdditionalFields = make(map[string]any)
+		}
+		message.Params.Meta.AdditionalFields[requestIDMetaKey] = id
+	})
+	return []server.ServerOption{
+		server.WithToolHandlerMiddleware(c.middleware),
+	}
+}
+
+func (c *inflightCalls) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		meta := request.Params.Meta
+		if meta == nil || meta.AdditionalFields == nil {
+			return next(ctx, request)
+		}
+		id, ok := meta.AdditionalFields[requestIDMetaKey]
+		if !ok {
+			return next(ctx, request)
+		}
+		delete(meta.AdditionalFields, requestIDMetaKey)
+
+		ctx, done := c.start(ctx, callKey(ctx, id))
+		defer done()
+		return next(ctx, request)
+	}
+}
+
+// track returns a copy of ctx that notifications/cancelled for request id of
+// sessionID cancels, until done is called. It is for the requests of extra
+// methods, which never reach the tool middleware.
+func (c *inflightCalls) track(ctx context.Context, sessionID string, id any) (_ context.Context, done func()) {
+	return c.start(ctx, sessionKey(sessionID, id))
+}
+
+// start registers a cancellable copy of ctx under key until done is called.
+func (c *inflightCalls) start(ctx context.Context, key string) (_ context.Context, done func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	c.cancels[key] = cancel
+	c.mu.Unlock()
+	return ctx, func() {
+		c.mu.Lock()
+		delete(c.cancels, key)
+		c.mu.Unlock()
+		cancel()
+	}
+}
+
+// handleCancelled is the notifications/cancelled handler.
+func (c *inflightCalls) handleCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	id, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+	key := callKey(ctx, id)
+	c.mu.Lock()
+	cancel, ok := c.cancels[key]
+	c.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// callKey scopes a request id to the session it arrived on, since ids are
+// only unique per client.
+func callKey(ctx context.Context, id any) string {
+	sessionID := ""
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+	return sessionKey(sessionID, id)
+}
+
+func sessionKey(sessionID string, id any) string {
+	return fmt.Sprintf("%s/%v", sessionID, id)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// cancel sends notifications/cancelled for request id on the session of c.
+func cancel(t *testing.T, c *client.Client, id int64) {
+	t.Helper()
+	err := c.GetTransport().SendNotification(context.Background(), mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: "notifications/cancelled",
+			Params: mcp.NotificationParams{AdditionalFields: map[string]any{"requestId": id, "reason": "test"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("sending notifications/cancelled: %v", err)
+	}
+}
+
+// firstCallID is the JSON-RPC id of the first request after initialize,
+// which is request 1 of every client.
+const firstCallID = 2
+
+// waitFor fails t unless ch receives within a few seconds.
+func waitFor(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting until %s", what)
+	}
+}
+
+func TestCancellation(t *testing.T) {
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			t.Run("notifications/cancelled", func(t *testing.T) {
+				upstream := newFakeUpstream(t)
+				started, aborted := upstream.block()
+				c := tr.connect(t, testConfig(upstream.URL))
+
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					ctx, stop := context.WithTimeout(context.Background(), 15*time.Second)
+					defer stop()
+					req := mcp.CallToolRequest{}
+					req.Params.Name = "get_genres"
+					req.Params.Arguments = map[string]any{}
+					c.CallTool(ctx, req)
+				}()
+				waitFor(t, started, "the upstream request starts")
+				cancel(t, c, firstCallID)
+				waitFor(t, aborted, "the upstream request is aborted")
+				waitFor(t, done, "the call returns")
+			})
+
+			t.Run("CALL_TIMEOUT", func(t *testing.T) {
+				upstream := newFakeUpstream(t)
+				_, aborted := upstream.block()
+				cfg := testConfig(upstream.URL)
+				cfg.CallTimeout = 200 * time.Millisecond
+				c := tr.connect(t, cfg)
+
+				began := time.Now()
+				res := callTool(t, c, "get_genres", map[string]any{})
+				if !res.IsError {
+					t.Errorf("IsError = false, want the call to time out")
+				}
+				if elapsed := time.Since(began); elapsed > 3*time.Second {
+					t.Errorf("call took %v, want it cut off by the 200ms call timeout", elapsed)
+				}
+				waitFor(t, aborted, "the upstream request is aborted")
+			})
+		})
+	}
+}
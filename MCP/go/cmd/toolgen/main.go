@@ -0,0 +1,470 @@
+// Command toolgen reads the Listen API openapi.yaml and generates the
+// operation table in package openapi and the typed client methods in package
+// listenapi, and copies the spec into package openapi for embedding. It is
+// run through `go generate`; see openapi/openapi.go.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	specPath := flag.String("spec", "openapi.yaml", "path to the OpenAPI spec")
+	opsOut := flag.String("ops", "", "output file for the openapi operation table")
+	clientOut := flag.String("client", "", "output file for the listenapi client methods")
+	embedOut := flag.String("embed", "", "output file for a copy of the spec to embed in the binary")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatalf("Failed to read spec: %v", err)
+	}
+	var doc spec
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		log.Fatalf("Failed to parse spec: %v", err)
+	}
+	ops, err := doc.operations()
+	if err != nil {
+		log.Fatalf("Failed to build operations: %v", err)
+	}
+
+	if *opsOut != "" {
+		if err := render(*opsOut, opsTemplate, ops); err != nil {
+			log.Fatalf("Failed to write %s: %v", *opsOut, err)
+		}
+	}
+	if *clientOut != "" {
+		if err := render(*clientOut, clientTemplate, ops); err != nil {
+			log.Fatalf("Failed to write %s: %v", *clientOut, err)
+		}
+	}
+	if *embedOut != "" {
+		if err := os.WriteFile(*embedOut, data, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", *embedOut, err)
+		}
+	}
+}
+
+// spec is the subset of an OpenAPI 3 document that toolgen understands.
+type spec struct {
+	Paths      ordered[ordered[operation]] `yaml:"paths"`
+	Components struct {
+		Parameters map[string]parameter `yaml:"parameters"`
+		Schemas    map[string]schema    `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type operation struct {
+	OperationID string      `yaml:"operationId"`
+	Summary     string      `yaml:"summary"`
+	Description string      `yaml:"description"`
+	Tags        []string    `yaml:"tags"`
+	Parameters  []parameter `yaml:"parameters"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema schema `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema schema `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"responses"`
+}
+
+type parameter struct {
+	Ref         string `yaml:"$ref"`
+	Name        string `yaml:"name"`
+	In          string `yaml:"in"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	Schema      schema `yaml:"schema"`
+}
+
+type schema struct {
+	Ref         string          `yaml:"$ref"`
+	Type        any             `yaml:"type"` // a name, or a list of names in OpenAPI 3.1
+	Description string          `yaml:"description"`
+	Enum        []any           `yaml:"enum"`
+	Default     any             `yaml:"default"`
+	Required    []string        `yaml:"required"`
+	Properties  ordered[schema] `yaml:"properties"`
+}
+
+// typeName returns the JSON Schema type of s, ignoring "null" in type lists.
+func (s schema) typeName() string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if name, ok := v.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	return "string"
+}
+
+// ordered is a YAML mapping that keeps the key order of the document, so that
+// generated output follows the spec.
+type ordered[T any] []entry[T]
+
+type entry[T any] struct {
+	Key   string
+	Value T
+}
+
+func (o *ordered[T]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var value T
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return err
+		}
+		*o = append(*o, entry[T]{Key: node.Content[i].Value, Value: value})
+	}
+	return nil
+}
+
+var httpMethods = map[string]string{
+	"get":    "http.MethodGet",
+	"post":   "http.MethodPost",
+	"put":    "http.MethodPut",
+	"patch":  "http.MethodPatch",
+	"delete": "http.MethodDelete",
+}
+
+// genOperation is what the templates see for each operation.
+type genOperation struct {
+	ID          string
+	GoName      string // listenapi method name
+	Method      string // upper case HTTP method
+	MethodConst string // net/http constant
+	Path        string
+	PathExpr    string // Go expression that builds Path from the path parameters
+	Tag         string
+	ToolName    string
+	Summary     string
+	Description string
+	Response    string // models type name
+	Params      []genParam
+	PathParams  []genParam
+	HasQuery    bool
+	HasForm     bool
+}
+
+type genParam struct {
+	Name        string
+	GoName      string // Go identifier for a path parameter
+	In          string
+	Type        string
+	Description string
+	Required    bool
+	Enum        []any
+	Default     any
+}
+
+func (d *spec) operations() ([]genOperation, error) {
+	var ops []genOperation
+	for _, path := range d.Paths {
+		for _, item := range path.Value {
+			methodConst, ok := httpMethods[item.Key]
+			if !ok {
+				continue
+			}
+			op, err := d.operation(path.Key, item.Key, methodConst, item.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(item.Key), path.Key, err)
+			}
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+func (d *spec) operation(path, method, methodConst string, op operation) (genOperation, error) {
+	if op.OperationID == "" {
+		return genOperation{}, fmt.Errorf("missing operationId")
+	}
+	g := genOperation{
+		ID:          op.OperationID,
+		GoName:      strings.ToUpper(op.OperationID[:1]) + op.OperationID[1:],
+		Method:      strings.ToUpper(method),
+		MethodConst: methodConst,
+		Path:        path,
+		ToolName:    toolName(method, path),
+		Summary:     op.Summary,
+		Description: op.Description,
+	}
+	if len(op.Tags) > 0 {
+		g.Tag = op.Tags[0]
+	}
+
+	for _, p := range op.Parameters {
+		if p.Ref != "" {
+			resolved, ok := d.Components.Parameters[refName(p.Ref)]
+			if !ok {
+				return genOperation{}, fmt.Errorf("unknown parameter %s", p.Ref)
+			}
+			p = resolved
+		}
+		if p.In != "path" && p.In != "query" {
+			continue
+		}
+		param := genParam{
+			Name:        p.Name,
+			GoName:      goIdent(p.Name),
+			In:          p.In,
+			Type:        p.Schema.typeName(),
+			Description: p.Description,
+			Required:    p.Required || p.In == "path",
+			Enum:        p.Schema.Enum,
+			Default:     p.Schema.Default,
+		}
+		g.Params = append(g.Params, param)
+		if p.In == "path" {
+			g.PathParams = append(g.PathParams, param)
+		} else {
+			g.HasQuery = true
+		}
+	}
+
+	if op.RequestBody != nil {
+		content, ok := op.RequestBody.Content["application/x-www-form-urlencoded"]
+		if !ok {
+			return genOperation{}, fmt.Errorf("only form-encoded request bodies are supported")
+		}
+		form := content.Schema
+		if form.Ref != "" {
+			resolved, ok := d.Components.Schemas[refName(form.Ref)]
+			if !ok {
+				return genOperation{}, fmt.Errorf("unknown schema %s", form.Ref)
+			}
+			form = resolved
+		}
+		for _, prop := range form.Properties {
+			g.Params = append(g.Params, genParam{
+				Name:        prop.Key,
+				In:          "form",
+				Type:        prop.Value.typeName(),
+				Description: prop.Value.Description,
+				Required:    contains(form.Required, prop.Key),
+				Enum:        prop.Value.Enum,
+				Default:     prop.Value.Default,
+			})
+		}
+		g.HasForm = true
+	}
+
+	g.PathExpr = pathExpr(path, g.PathParams)
+
+	ok, err := d.responseModel(op, &g.Response)
+	if err != nil {
+		return genOperation{}, err
+	}
+	if !ok {
+		return genOperation{}, fmt.Errorf("no JSON response schema for 200")
+	}
+	return g, nil
+}
+
+func (d *spec) responseModel(op operation, name *string) (bool, error) {
+	resp, ok := op.Responses["200"]
+	if !ok {
+		return false, nil
+	}
+	content, ok := resp.Content["application/json"]
+	if !ok || content.Schema.Ref == "" {
+		return false, nil
+	}
+	*name = refName(content.Schema.Ref)
+	if _, ok := d.Components.Schemas[*name]; !ok {
+		return false, fmt.Errorf("unknown schema %s", content.Schema.Ref)
+	}
+	return true, nil
+}
+
+// toolName derives the MCP tool name from the method and path, e.g.
+// GET /podcasts/{id}/recommendations becomes get_podcasts_id_recommendations.
+func toolName(method, path string) string {
+	parts := []string{strings.ToLower(method)}
+	for _, seg := range strings.Split(path, "/") {
+		seg = strings.Trim(seg, "{}")
+		if seg != "" {
+			parts = append(parts, seg)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// pathExpr turns /podcasts/{id}/audience into
+// "/podcasts/"+url.PathEscape(id)+"/audience", so that a value containing
+// "/" or "?" stays within its segment.
+func pathExpr(path string, params []genParam) string {
+	expr := strconv.Quote(path)
+	for _, p := range params {
+		expr = strings.ReplaceAll(expr, "{"+p.Name+"}", `"+url.PathEscape(`+p.GoName+`)+"`)
+	}
+	return strings.TrimSuffix(expr, `+""`)
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// goIdent turns domain_name into domainName.
+func goIdent(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// literal renders a scalar from the spec as a Go expression.
+func literal(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(v)
+	case bool, int, int64, float64:
+		return fmt.Sprint(v)
+	default:
+		return strconv.Quote(fmt.Sprint(v))
+	}
+}
+
+func literals(vs []any) string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = literal(v)
+	}
+	return strings.Join(out, ", ")
+}
+
+func render(path string, tmpl *template.Template, ops []genOperation) error {
+	sorted := append([]genOperation(nil), ops...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, sorted); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("generated code does not parse: %w", err)
+	}
+	return os.WriteFile(path, src, 0o644)
+}
+
+var funcs = template.FuncMap{
+	"quote":    strconv.Quote,
+	"literal":  literal,
+	"literals": literals,
+}
+
+var opsTemplate = template.Must(template.New("ops").Funcs(funcs).Parse(`// Code generated by toolgen from openapi.yaml; DO NOT EDIT.
+
+package openapi
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+)
+
+// Operations lists every operation in openapi.yaml, ordered by path.
+var Operations = []Operation{
+{{- range .}}
+	{
+		ID:          {{quote .ID}},
+		Method:      {{quote .Method}},
+		Path:        {{quote .Path}},
+		Tag:         {{quote .Tag}},
+		ToolName:    {{quote .ToolName}},
+		Summary:     {{quote .Summary}},
+		Description: {{quote .Description}},
+		Params: []Param{
+		{{- range .Params}}
+			{
+				Name:        {{quote .Name}},
+				In:          {{if eq .In "path"}}InPath{{else if eq .In "query"}}InQuery{{else}}InForm{{end}},
+				Type:        {{quote .Type}},
+				Description: {{quote .Description}},
+				{{- if .Required}}
+				Required:    true,
+				{{- end}}
+				{{- if .Enum}}
+				Enum:        []any{ {{- literals .Enum -}} },
+				{{- end}}
+				{{- if ne .Default nil}}
+				Default:     {{literal .Default}},
+				{{- end}}
+			},
+		{{- end}}
+		},
+		Response: reflect.TypeFor[models.{{.Response}}](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.{{.GoName}}(ctx
+				{{- range .PathParams}}, path[{{quote .Name}}]{{end}}
+				{{- if .HasQuery}}, query{{end}}
+				{{- if .HasForm}}, form{{end}})
+		},
+	},
+{{- end}}
+}
+`))
+
+var clientTemplate = template.Must(template.New("client").Funcs(funcs).Parse(`// Code generated by toolgen from openapi.yaml; DO NOT EDIT.
+
+package listenapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+)
+
+// Typed wrappers for every operation in openapi.yaml. Method names follow
+// the spec's operationId; each returns the response both as sent and
+// decoded into its model.
+{{range .}}
+// {{.GoName}} calls {{.Method}} {{.Path}}.
+func (c *Client) {{.GoName}}(ctx context.Context
+	{{- range .PathParams}}, {{.GoName}} string{{end}}
+	{{- if .HasQuery}}, query url.Values{{end}}
+	{{- if .HasForm}}, form url.Values{{end}}) (*Response[models.{{.Response}}], error) {
+	return call[models.{{.Response}}](ctx, c, {{.MethodConst}}, {{.PathExpr}}, {{if .HasQuery}}query{{else}}nil{{end}}, {{if .HasForm}}form{{else}}nil{{end}})
+}
+{{end}}`))
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// methodCompletionComplete is the method of argument completion, which
+// mcp-go does not define.
+const methodCompletionComplete mcp.MCPMethod = "completion/complete"
+
+// maxCompletions is the most values one completion may hold.
+const maxCompletions = 100
+
+// How long completion candidates are kept. Genres, languages and regions
+// hardly ever change; podcast suggestions are per typed prefix.
+const (
+	referenceListTTL = 24 * time.Hour
+	typeaheadTTL     = 15 * time.Minute
+)
+
+// completionCacheEntries is how many candidate lists are kept.
+const completionCacheEntries = 1000
+
+// option is a candidate value of an argument, with the label it is matched
+// by, e.g. genre 127 labelled Technology.
+type option struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// completer returns the values for an argument that match what the user has
+// typed so far, best first.
+type completer func(ctx context.Context, typed string) ([]string, error)
+
+// completions answers completion/complete for the arguments of prompts and
+// resource templates that take genres, languages, regions or podcasts. The
+// candidates come from the API and are cached per tenant, whatever the
+// response cache is configured to do.
+type completions struct {
+	client *listenapi.Client
+	base   *config.APIConfig // for sessions that carry no configuration
+	cache  listenapi.CacheStore
+
+	byArgument map[string]completer            // prompt arguments, by name
+	byTemplate map[string]map[string]completer // resource template variables, by URI template and name
+}
+
+func newCompletions(client *listenapi.Client, cfg *config.APIConfig) *completions {
+	c := &completions{
+		client: client,
+		base:   cfg,
+		cache:  listenapi.NewMemoryStore(completionCacheEntries),
+	}
+	c.byArgument = map[string]completer{
+		"genre_id":         c.genre,
+		"genre_ids":        listOf(c.genre),
+		"language":         c.language,
+		"region":           c.region,
+		"publisher_region": c.region,
+		"podcast_id":       c.podcast,
+	}
+	c.byTemplate = map[string]map[string]completer{
+		podcastURIPrefix + "{id}": {"id": c.podcast},
+	}
+	return c
+}
+
+// register adds the completion/complete method to extra.
+func (c *completions) register(extra *extraMethods) {
+	extra.add(methodCompletionComplete, c.complete)
+}
+
+// complete is the completion/complete handler. Arguments nothing is known
+// about get no values rather than an error, as the spec asks.
+func (c *completions) complete(ctx context.Context, sessionID string, params json.RawMessage) (any, error) {
+	var p struct {
+		Ref struct {
+			Type string `json:"type"`
+			Name string `json:"name"`
+			URI  string `json:"uri"`
+		} `json:"ref"`
+		Argument struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"argument"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams("invalid params: %v", err)
+	}
+
+	var complete completer
+	switch p.Ref.Type {
+	case "ref/prompt":
+		spec, ok := findPrompt(p.Ref.Name)
+		if !ok {
+			return nil, invalidParams("unknown prompt %q", p.Ref.Name)
+		}
+		for _, arg := range spec.Args {
+			if arg.Name == p.Argument.Name {
+				complete = c.byArgument[arg.Name]
+			}
+		}
+	case "ref/resource":
+		if !knownResource(p.Ref.URI) {
+			return nil, invalidParams("unknown resource %q", p.Ref.URI)
+		}
+		complete = c.byTemplate[p.Ref.URI][p.Argument.Name]
+	default:
+		return nil, invalidParams("unknown reference type %q", p.Ref.Type)
+	}
+
+	result := mcp.CompleteResult{}
+	result.Completion.Values = []string{}
+	if complete == nil {
+		return result, nil
+	}
+	values, err := complete(ctx, p.Argument.Value)
+	if err != nil {
+		return nil, err
+	}
+	result.Completion.Total = len(values)
+	if len(values) > maxCompletions {
+		values = values[:maxCompletions]
+		result.Completion.HasMore = true
+	}
+	result.Completion.Values = append(result.Completion.Values, values...)
+	return result, nil
+}
+
+// findPrompt returns the prompt called name.
+func findPrompt(name string) (promptSpec, bool) {
+	for _, spec := range researchPrompts {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return promptSpec{}, false
+}
+
+// knownResource reports whether uri is one of the resources or resource
+// templates.
+func knownResource(uri string) bool {
+	for _, spec := range append(resourceTemplates[:len(resourceTemplates):len(resourceTemplates)], staticResources...) {
+		if spec.URI == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// genre completes genre ids by id or by name.
+func (c *completions) genre(ctx context.Context, typed string) ([]string, error) {
+	options, err := c.options(ctx, "genres", "", referenceListTTL, func(ctx context.Context) ([]option, error) {
+		resp, err := c.client.GetGenres(ctx, url.Values{"top_level_only": {"0"}})
+		if err != nil {
+			return nil, err
+		}
+		if resp.Value == nil {
+			return nil, errors.New("unexpected genres response")
+		}
+		options := make([]option, len(resp.Value.Genres))
+		for i, g := range resp.Value.Genres {
+			options[i] = option{Value: strconv.Itoa(g.Id), Label: g.Name}
+		}
+		return options, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match(options, typed), nil
+}
+
+// language completes language names.
+func (c *completions) language(ctx context.Context, typed string) ([]string, error) {
+	options, err := c.options(ctx, "languages", "", referenceListTTL, func(ctx context.Context) ([]option, error) {
+		resp, err := c.client.GetLanguages(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Value == nil {
+			return nil, errors.New("unexpected languages response")
+		}
+		options := make([]option, len(resp.Value.Languages))
+		for i, l := range resp.Value.Languages {
+			options[i] = option{Value: l, Label: l}
+		}
+		return options, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match(options, typed), nil
+}
+
+// region completes region codes by code or by name.
+func (c *completions) region(ctx context.Context, typed string) ([]string, error) {
+	options, err := c.options(ctx, "regions", "", referenceListTTL, func(ctx context.Context) ([]option, error) {
+		resp, err := c.client.GetRegions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Value == nil {
+			return nil, errors.New("unexpected regions response")
+		}
+		options := make([]option, 0, len(resp.Value.Regions))
+		for code, name := range resp.Value.Regions {
+			label, _ := name.(string)
+			options = append(options, option{Value: code, Label: label})
+		}
+		sort.Slice(options, func(i, j int) bool { return options[i].Value < options[j].Value })
+		return options, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match(options, typed), nil
+}
+
+// podcast completes podcast ids by title, with the podcasts typeahead
+// suggests for what was typed.
+func (c *completions) podcast(ctx context.Context, typed string) ([]string, error) {
+	typed = strings.TrimSpace(typed)
+	if typed == "" {
+		return nil, nil
+	}
+	options, err := c.options(ctx, "typeahead", strings.ToLower(typed), typeaheadTTL, func(ctx context.Context) ([]option, error) {
+		resp, err := c.client.Typeahead(ctx, url.Values{"q": {typed}, "show_podcasts": {"1"}})
+		if err != nil {
+			return nil, err
+		}
+		if resp.Value == nil {
+			return nil, errors.New("unexpected typeahead response")
+		}
+		options := make([]option, len(resp.Value.Podcasts))
+		for i, p := range resp.Value.Podcasts {
+			options[i] = option{Value: p.Id, Label: p.Title_original}
+		}
+		return options, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, len(options))
+	for i, o := range options {
+		values[i] = o.Value
+	}
+	return values, nil
+}
+
+// options returns the candidates called name for the tenant of ctx, from
+// the cache or else from fetch. key tells apart candidates that depend on
+// what was typed.
+func (c *completions) options(ctx context.Context, name, key string, ttl time.Duration, fetch func(ctx context.Context) ([]option, error)) ([]option, error) {
+	cfg := listenapi.ConfigFrom(ctx)
+	if cfg == nil {
+		cfg = c.base
+		ctx = listenapi.WithConfig(ctx, cfg)
+	}
+	cacheKey := tenantKey(cfg) + "/" + name + "/" + key
+	if data, ok := c.cache.Get(cacheKey); ok {
+		var options []option
+		if json.Unmarshal(data, &options) == nil {
+			return options, nil
+		}
+	}
+
+	options, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(options); err == nil {
+		c.cache.Set(cacheKey, data, ttl)
+	}
+	return options, nil
+}
+
+// tenantKey identifies the tenant of cfg without holding its credentials.
+func tenantKey(cfg *config.APIConfig) string {
+	h := sha256.New()
+	for _, part := range []string{cfg.BaseURL, cfg.APIKey, cfg.BearerToken, cfg.BasicAuth} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// match returns the values of the options that match typed, ignoring case:
+// first those whose value starts with it, then those whose label does, then
+// those with a word in the label that does, and last those whose label
+// merely contains it. Everything matches an empty typed.
+func match(options []option, typed string) []string {
+	typed = strings.ToLower(strings.TrimSpace(typed))
+	type ranked struct {
+		value string
+		rank  int
+	}
+	var matches []ranked
+	for _, o := range options {
+		value, label := strings.ToLower(o.Value), strings.ToLower(o.Label)
+		rank := -1
+		switch {
+		case strings.HasPrefix(value, typed):
+			rank = 0
+		case strings.HasPrefix(label, typed):
+			rank = 1
+		case strings.Contains(label, " "+typed) || strings.Contains(label, "&"+typed) || strings.Contains(label, "-"+typed):
+			rank = 2
+		case strings.Contains(label, typed):
+			rank = 3
+		}
+		if rank >= 0 {
+			matches = append(matches, ranked{o.Value, rank})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].rank < matches[j].rank })
+	values := make([]string, len(matches))
+	for i, m := range matches {
+		values[i] = m.value
+	}
+	return values
+}
+
+// listOf completes the last item of a comma-separated list with complete,
+// leaving the items before it as they are.
+func listOf(complete completer) completer {
+	return func(ctx context.Context, typed string) ([]string, error) {
+		head, last := "", typed
+		if i := strings.LastIndex(typed, ","); i >= 0 {
+			head, last = typed[:i+1], typed[i+1:]
+		}
+		values, err := complete(ctx, last)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			values[i] = head + v
+		}
+		return values, nil
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeclareCompletions(t *testing.T) {
+	initialize := `{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-03-26","capabilities":{"tools":{}},"serverInfo":{"name":"s","version":"1"}}}`
+	declared := `{"id":1,"jsonrpc":"2.0","result":{"capabilities":{"completions":{},"tools":{}},"protocolVersion":"2025-03-26","serverInfo":{"name":"s","version":"1"}}}`
+	toolResult := `{"jsonrpc":"2.0","id":2,"result":{"content":[{"type":"text","text":"\"serverInfo\""}]}}`
+
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"stdio line", initialize + "\n", declared + "\n"},
+		{"server-sent event", "event: message\ndata: " + initialize + "\n\n", "event: message\ndata: " + declared + "\n\n"},
+		{"other result mentioning serverInfo", toolResult + "\n", toolResult + "\n"},
+		{"not JSON", `"serverInfo"`, `"serverInfo"`},
+		{"ping", ": ping\n\n", ": ping\n\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(declareCompletions([]byte(tc.in))); got != tc.want {
+				t.Errorf("declareCompletions =\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	options := []option{
+		{"127", "Technology"},
+		{"131", "Tech News"},
+		{"93", "Business"},
+		{"99", "Tech-Business News"},
+		{"1271", "Biotech"},
+	}
+	cases := []struct {
+		typed string
+		want  []string
+	}{
+		{"", []string{"127", "131", "93", "99", "1271"}},
+		{"tech", []string{"127", "131", "99", "1271"}},
+		{"BUS", []string{"93", "99"}},
+		{"127", []string{"127", "1271"}},
+		{"news", []string{"131", "99"}},
+		{"podcasts", []string{}},
+	}
+	for _, tc := range cases {
+		if got := match(options, tc.typed); strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("match(%q) = %q, want %q", tc.typed, got, tc.want)
+		}
+	}
+}
@@ -3,6 +3,52 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRequestTimeout bounds a single HTTP request to the Listen API.
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultCallTimeout bounds everything one tool call does upstream.
+	DefaultCallTimeout = 60 * time.Second
+	// DefaultSessionTTL is how long an idle HTTP session is kept.
+	DefaultSessionTTL = 30 * time.Minute
+	// DefaultSessionCleanupInterval is how often expired sessions are removed.
+	DefaultSessionCleanupInterval = time.Minute
+	// DefaultMaxRetries is how often a failed idempotent request is retried.
+	DefaultMaxRetries = 3
+	// DefaultRetryBaseDelay is the backoff before the first retry; it doubles
+	// for each further retry.
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	// DefaultRetryMaxDelay caps the backoff between two retries.
+	DefaultRetryMaxDelay = 10 * time.Second
+	// DefaultRateLimit is how many requests per second each API key may send.
+	DefaultRateLimit = 5
+	// DefaultRateLimitBurst is how many requests an idle API key may send at
+	// once before DefaultRateLimit applies.
+	DefaultRateLimitBurst = 10
+	// DefaultCacheMaxEntries is how many responses the in-memory cache holds.
+	DefaultCacheMaxEntries = 1000
+	// DefaultCassetteDir is where RECORD and REPLAY mode keep recordings.
+	DefaultCassetteDir = "cassettes"
+	// DefaultSubscriptionPollInterval is how often a subscribed podcast is
+	// checked for new episodes while the quota allows it.
+	DefaultSubscriptionPollInterval = 15 * time.Minute
+)
+
+// Response cache backends, selected with the CACHE environment variable.
+const (
+	CacheMemory = "memory"
+	CacheDisk   = "disk"
+	CacheOff    = "off"
+)
+
+// Cassette modes, selected with the CASSETTE_MODE environment variable.
+const (
+	CassetteRecord = "record" // Record every upstream exchange
+	CassetteReplay = "replay" // Answer from recordings, never touching the network
 )
 
 type APIConfig struct {
@@ -11,6 +57,43 @@ type APIConfig struct {
 	APIKey      string // For API key authentication
 	BasicAuth   string // For basic authentication
 	Port        string // For server port configuration
+
+	BearerTokenHeader string // Header that carries BearerToken, Authorization if empty
+	BasicAuthHeader   string // Header that carries BasicAuth, Authorization if empty
+
+	RequestTimeout time.Duration // Deadline for each upstream HTTP request
+	CallTimeout    time.Duration // Overall deadline for the upstream work of one tool call
+
+	MaxRetries     int           // Retries of an idempotent request after 429, 5xx or transport errors
+	RetryBaseDelay time.Duration // Backoff before the first retry, doubled for each further one
+	RetryMaxDelay  time.Duration // Upper bound on the backoff between two retries
+
+	RateLimit      float64 // Requests per second allowed for each API key, 0 for no limit
+	RateLimitBurst int     // Requests an idle API key may send at once
+
+	// SoftQuota is the number of requests per billing cycle after which tools
+	// refuse to call the API, 0 for none. SoftQuotaPercent instead sets it as
+	// a percentage of the free quota the API reports for the key's plan.
+	SoftQuota        int
+	SoftQuotaPercent float64
+
+	Cache           string                   // Response cache backend: CacheMemory, CacheDisk or CacheOff
+	CacheDir        string                   // Directory of the disk cache, a per-user cache directory if empty
+	CacheMaxEntries int                      // Responses the memory cache holds
+	CacheTTLs       map[string]time.Duration // Cache lifetimes by endpoint path pattern, overriding the defaults
+
+	CassetteMode string // CassetteRecord, CassetteReplay, or empty to talk to the API normally
+	CassetteDir  string // Directory of the recordings, DefaultCassetteDir if empty
+
+	SessionTTL             time.Duration // Idle time after which an HTTP session expires
+	SessionCleanupInterval time.Duration // How often expired HTTP sessions are removed
+
+	SubscriptionPollInterval time.Duration // How often subscribed podcasts are checked for new episodes, at the least
+
+	// AllowAPIKeyArgument exposes an optional X-ListenAPI-Key tool argument
+	// that overrides APIKey for a single call. Off by default so that the key
+	// never has to pass through the model.
+	AllowAPIKeyArgument bool
 }
 
 func LoadAPIConfig() (*APIConfig, error) {
@@ -28,21 +111,263 @@ func LoadAPIConfig() (*APIConfig, error) {
 		transport = os.Getenv("transport")
 	}
 	
-	// For STDIO mode (transport is not "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL is required from environment
-	if transport != "http" && transport != "HTTP" && transport != "https" && transport != "HTTPS" && baseURL == "" {
+	// For STDIO mode (transport is not "http"/"HTTP"/"https"/"HTTPS"/"sse"/"SSE"), API_BASE_URL is required from environment
+	if transport != "http" && transport != "HTTP" && transport != "https" && transport != "HTTPS" && transport != "sse" && transport != "SSE" && baseURL == "" {
 		return nil, fmt.Errorf("API_BASE_URL environment variable not set")
 	}
 	
-	// For HTTP/HTTPS mode (transport is "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL comes from headers
+	// For HTTP/HTTPS/SSE mode (transport is "http"/"HTTP"/"https"/"HTTPS"/"sse"/"SSE"), API_BASE_URL comes from headers
 	// so we don't require it from environment variables
 
+	apiKey, err := apiKeyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	allowAPIKeyArgument, err := boolFromEnv("ALLOW_API_KEY_ARGUMENT")
+	if err != nil {
+		return nil, err
+	}
+
+	requestTimeout, err := durationFromEnv("REQUEST_TIMEOUT", DefaultRequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	callTimeout, err := durationFromEnv("CALL_TIMEOUT", DefaultCallTimeout)
+	if err != nil {
+		return nil, err
+	}
+	maxRetries, err := intFromEnv("MAX_RETRIES", DefaultMaxRetries)
+	if err != nil {
+		return nil, err
+	}
+	retryBaseDelay, err := durationFromEnv("RETRY_BASE_DELAY", DefaultRetryBaseDelay)
+	if err != nil {
+		return nil, err
+	}
+	retryMaxDelay, err := durationFromEnv("RETRY_MAX_DELAY", DefaultRetryMaxDelay)
+	if err != nil {
+		return nil, err
+	}
+	rateLimit, err := floatFromEnv("RATE_LIMIT", DefaultRateLimit)
+	if err != nil {
+		return nil, err
+	}
+	rateLimitBurst, err := intFromEnv("RATE_LIMIT_BURST", DefaultRateLimitBurst)
+	if err != nil {
+		return nil, err
+	}
+	softQuota, softQuotaPercent, err := softQuotaFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	cache := strings.ToLower(orDefault(os.Getenv("CACHE"), CacheMemory))
+	if cache != CacheMemory && cache != CacheDisk && cache != CacheOff {
+		return nil, fmt.Errorf("invalid CACHE %q: must be memory, disk or off", cache)
+	}
+	cacheMaxEntries, err := intFromEnv("CACHE_MAX_ENTRIES", DefaultCacheMaxEntries)
+	if err != nil {
+		return nil, err
+	}
+	cacheTTLs, err := cacheTTLsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	cassetteMode := strings.ToLower(os.Getenv("CASSETTE_MODE"))
+	if cassetteMode != "" && cassetteMode != CassetteRecord && cassetteMode != CassetteReplay {
+		return nil, fmt.Errorf("invalid CASSETTE_MODE %q: must be record or replay", cassetteMode)
+	}
+	sessionTTL, err := durationFromEnv("SESSION_TTL", DefaultSessionTTL)
+	if err != nil {
+		return nil, err
+	}
+	sessionCleanupInterval, err := durationFromEnv("SESSION_CLEANUP_INTERVAL", DefaultSessionCleanupInterval)
+	if err != nil {
+		return nil, err
+	}
+	subscriptionPollInterval, err := durationFromEnv("SUBSCRIPTION_POLL_INTERVAL", DefaultSubscriptionPollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	bearerToken, bearerTokenHeader := os.Getenv("BEARER_TOKEN"), os.Getenv("BEARER_TOKEN_HEADER")
+	basicAuth, basicAuthHeader := os.Getenv("BASIC_AUTH"), os.Getenv("BASIC_AUTH_HEADER")
+	if bearerToken != "" && basicAuth != "" && strings.EqualFold(orDefault(bearerTokenHeader, "Authorization"), orDefault(basicAuthHeader, "Authorization")) {
+		return nil, fmt.Errorf("BEARER_TOKEN and BASIC_AUTH would both be sent in the %s header; set BEARER_TOKEN_HEADER or BASIC_AUTH_HEADER", orDefault(bearerTokenHeader, "Authorization"))
+	}
+
 	return &APIConfig{
-		BaseURL:     baseURL,
-		BearerToken: os.Getenv("BEARER_TOKEN"),
-		APIKey:      os.Getenv("API_KEY"),
-		BasicAuth:   os.Getenv("BASIC_AUTH"),
-		Port:        port,
+		BaseURL:        baseURL,
+		BearerToken:    bearerToken,
+		APIKey:         apiKey,
+		BasicAuth:      basicAuth,
+		Port:           port,
+
+		BearerTokenHeader: bearerTokenHeader,
+		BasicAuthHeader:   basicAuthHeader,
+
+		RequestTimeout: requestTimeout,
+		CallTimeout:    callTimeout,
+
+		MaxRetries:     maxRetries,
+		RetryBaseDelay: retryBaseDelay,
+		RetryMaxDelay:  retryMaxDelay,
+
+		RateLimit:      rateLimit,
+		RateLimitBurst: rateLimitBurst,
+
+		SoftQuota:        softQuota,
+		SoftQuotaPercent: softQuotaPercent,
+
+		Cache:           cache,
+		CacheDir:        os.Getenv("CACHE_DIR"),
+		CacheMaxEntries: cacheMaxEntries,
+		CacheTTLs:       cacheTTLs,
+
+		CassetteMode: cassetteMode,
+		CassetteDir:  os.Getenv("CASSETTE_DIR"),
+
+		SessionTTL:             sessionTTL,
+		SessionCleanupInterval: sessionCleanupInterval,
+
+		SubscriptionPollInterval: subscriptionPollInterval,
+
+		AllowAPIKeyArgument: allowAPIKeyArgument,
 	}, nil
 }
 
+// Redact replaces every credential held by c that occurs in s, so that
+// error text and logs never carry secrets.
+func (c *APIConfig) Redact(s string) string {
+	return RedactSecrets(s, c.APIKey, c.BearerToken, c.BasicAuth)
+}
+
+// RedactSecrets replaces each non-empty secret that occurs in s.
+func RedactSecrets(s string, secrets ...string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, "[REDACTED]")
+		}
+	}
+	return s
+}
+
+// apiKeyFromEnv reads the Listen API key from API_KEY, or from the file
+// named by API_KEY_FILE (e.g. a mounted secret) when API_KEY is unset.
+func apiKeyFromEnv() (string, error) {
+	if key := os.Getenv("API_KEY"); key != "" {
+		return key, nil
+	}
+	path := os.Getenv("API_KEY_FILE")
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read API_KEY_FILE: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func orDefault(val, def string) string {
+	if val == "" {
+		return def
+	}
+	return val
+}
+
+// boolFromEnv parses the named environment variable as a boolean, treating
+// unset as false.
+func boolFromEnv(name string) (bool, error) {
+	val := os.Getenv(name)
+	if val == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: must be true or false", name, val)
+	}
+	return b, nil
+}
 
+// durationFromEnv parses a Go duration such as "15s" from the named
+// environment variable, falling back to def when it is unset.
+func durationFromEnv(name string, def time.Duration) (time.Duration, error) {
+	val := os.Getenv(name)
+	if val == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive duration such as 30s", name, val)
+	}
+	return d, nil
+}
+
+// intFromEnv parses a non-negative integer from the named environment
+// variable, falling back to def when it is unset.
+func intFromEnv(name string, def int) (int, error) {
+	val := os.Getenv(name)
+	if val == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative integer", name, val)
+	}
+	return n, nil
+}
+
+// floatFromEnv parses a non-negative number from the named environment
+// variable, falling back to def when it is unset.
+func floatFromEnv(name string, def float64) (float64, error) {
+	val := os.Getenv(name)
+	if val == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative number", name, val)
+	}
+	return f, nil
+}
+
+// softQuotaFromEnv reads SOFT_QUOTA, either a number of requests such as
+// 20000 or a percentage of the plan's free quota such as 90%.
+func softQuotaFromEnv() (requests int, percent float64, err error) {
+	val := os.Getenv("SOFT_QUOTA")
+	if val == "" {
+		return 0, 0, nil
+	}
+	if p, ok := strings.CutSuffix(val, "%"); ok {
+		percent, err = strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return 0, 0, fmt.Errorf("invalid SOFT_QUOTA %q: a percentage must be between 0%% and 100%%", val)
+		}
+		return 0, percent, nil
+	}
+	requests, err = strconv.Atoi(val)
+	if err != nil || requests < 0 {
+		return 0, 0, fmt.Errorf("invalid SOFT_QUOTA %q: must be a number of requests or a percentage such as 90%%", val)
+	}
+	return requests, 0, nil
+}
+
+// cacheTTLsFromEnv reads CACHE_TTLS, a comma-separated list of endpoint
+// path patterns and cache lifetimes such as "/genres=48h,/search=0s". A
+// lifetime of 0s stops the endpoint from being cached.
+func cacheTTLsFromEnv() (map[string]time.Duration, error) {
+	val := os.Getenv("CACHE_TTLS")
+	if val == "" {
+		return nil, nil
+	}
+	ttls := make(map[string]time.Duration)
+	for _, entry := range strings.Split(val, ",") {
+		pattern, ttl, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		d, err := time.ParseDuration(ttl)
+		if !ok || !strings.HasPrefix(pattern, "/") || err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid CACHE_TTLS entry %q: must be /path/pattern=duration, e.g. /podcasts/*=1h", entry)
+		}
+		ttls[pattern] = d
+	}
+	return ttls, nil
+}
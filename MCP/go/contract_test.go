@@ -0,0 +1,431 @@
+package main
+
+// Contract tests: they parse openapi.yaml and fail as soon as the operation
+// table, the tools or the models disagree with it. When they fail after a
+// spec update, run `go generate ./...` and bring models/models.go and the
+// hand-written tools in tools/ in line with the spec.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/common"
+	"gopkg.in/yaml.v3"
+)
+
+// specPath is the spec that `go generate` reads; openapi.Spec is its copy.
+const specPath = "../../openapi.yaml"
+
+// contractSpec is the part of openapi.yaml the contract tests check.
+type contractSpec struct {
+	Paths      map[string]map[string]specOperation `yaml:"paths"`
+	Components struct {
+		Parameters map[string]specParam   `yaml:"parameters"`
+		Schemas    map[string]*specSchema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type specOperation struct {
+	OperationID string      `yaml:"operationId"`
+	Parameters  []specParam `yaml:"parameters"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema *specSchema `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema *specSchema `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"responses"`
+}
+
+type specParam struct {
+	Ref      string      `yaml:"$ref"`
+	Name     string      `yaml:"name"`
+	In       string      `yaml:"in"`
+	Required bool        `yaml:"required"`
+	Schema   *specSchema `yaml:"schema"`
+}
+
+type specSchema struct {
+	Ref        string                 `yaml:"$ref"`
+	Type       any                    `yaml:"type"`
+	Enum       []any                  `yaml:"enum"`
+	Required   []string               `yaml:"required"`
+	Properties map[string]*specSchema `yaml:"properties"`
+	Items      *specSchema            `yaml:"items"`
+	OneOf      []*specSchema          `yaml:"oneOf"`
+	AnyOf      []*specSchema          `yaml:"anyOf"`
+	AllOf      []*specSchema          `yaml:"allOf"`
+}
+
+// httpMethods are the keys of a path item that are operations.
+var httpMethods = map[string]bool{"get": true, "put": true, "post": true, "delete": true, "patch": true, "head": true, "options": true}
+
+func loadSpec(t *testing.T) *contractSpec {
+	t.Helper()
+	var doc contractSpec
+	if err := yaml.Unmarshal(openapi.Spec, &doc); err != nil {
+		t.Fatalf("parse openapi.yaml: %v", err)
+	}
+	return &doc
+}
+
+// resolve follows s's $ref to the component schema it names.
+func (d *contractSpec) resolve(s *specSchema) *specSchema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+// typeName is the JSON Schema type of s, ignoring "null" in type lists.
+func (d *contractSpec) typeName(s *specSchema) string {
+	s = d.resolve(s)
+	if s == nil {
+		return ""
+	}
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if name, ok := v.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	if len(s.Properties) > 0 {
+		return "object"
+	}
+	return ""
+}
+
+// specParams returns the path, query and form parameters of op, as
+// openapi.Param with the fields the contract covers.
+func (d *contractSpec) specParams(op specOperation) []openapi.Param {
+	var params []openapi.Param
+	for _, p := range op.Parameters {
+		if p.Ref != "" {
+			p = d.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+		}
+		if p.In != openapi.InPath && p.In != openapi.InQuery {
+			continue
+		}
+		s := d.resolve(p.Schema)
+		params = append(params, openapi.Param{Name: p.Name, In: p.In, Type: d.typeName(s), Required: p.Required, Enum: s.Enum})
+	}
+	if op.RequestBody != nil {
+		if content, ok := op.RequestBody.Content["application/x-www-form-urlencoded"]; ok {
+			body := d.resolve(content.Schema)
+			for name, prop := range body.Properties {
+				s := d.resolve(prop)
+				params = append(params, openapi.Param{
+					Name:     name,
+					In:       openapi.InForm,
+					Type:     d.typeName(s),
+					Required: containsString(body.Required, name),
+					Enum:     s.Enum,
+				})
+			}
+		}
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func operationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+func TestEmbeddedSpecIsCurrent(t *testing.T) {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		t.Fatalf("read %s: %v", specPath, err)
+	}
+	if !bytes.Equal(data, openapi.Spec) {
+		t.Errorf("openapi/openapi.yaml differs from %s; run go generate ./...", specPath)
+	}
+}
+
+func TestOperationsMatchSpec(t *testing.T) {
+	doc := loadSpec(t)
+	generated := make(map[string]openapi.Operation, len(openapi.Operations))
+	for _, op := range openapi.Operations {
+		generated[operationKey(op.Method, op.Path)] = op
+	}
+
+	seen := make(map[string]bool)
+	for path, item := range doc.Paths {
+		for method, specOp := range item {
+			if !httpMethods[method] {
+				continue
+			}
+			key := operationKey(method, path)
+			seen[key] = true
+			op, ok := generated[key]
+			if !ok {
+				t.Errorf("%s (%s) is in the spec but not in openapi.Operations", key, specOp.OperationID)
+				continue
+			}
+			if op.ID != specOp.OperationID {
+				t.Errorf("%s: operation id %q, spec has %q", key, op.ID, specOp.OperationID)
+			}
+
+			want := doc.specParams(specOp)
+			got := append([]openapi.Param(nil), op.Params...)
+			sort.Slice(got, func(i, j int) bool { return got[i].Name < got[j].Name })
+			if len(got) != len(want) {
+				t.Errorf("%s: parameters %v, spec has %v", key, paramNames(got), paramNames(want))
+				continue
+			}
+			for i := range want {
+				g, w := got[i], want[i]
+				if g.Name != w.Name || g.In != w.In || g.Type != w.Type || g.Required != w.Required || fmt.Sprint(g.Enum) != fmt.Sprint(w.Enum) {
+					t.Errorf("%s: parameter %s is {in: %s, type: %s, required: %v, enum: %v}, spec has {in: %s, type: %s, required: %v, enum: %v}",
+						key, g.Name, g.In, g.Type, g.Required, g.Enum, w.In, w.Type, w.Required, w.Enum)
+				}
+			}
+		}
+	}
+	for key := range generated {
+		if !seen[key] {
+			t.Errorf("%s is in openapi.Operations but not in the spec", key)
+		}
+	}
+}
+
+func paramNames(params []openapi.Param) []string {
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.Name
+	}
+	return names
+}
+
+func TestToolsMatchSpec(t *testing.T) {
+	doc := loadSpec(t)
+	cfg := testConfig("")
+	tools := make(map[string]int)
+	all := GetAll(cfg)
+	for i, tool := range all {
+		tools[tool.Definition.Name] = i
+	}
+	if len(all) != len(openapi.Operations) {
+		t.Errorf("%d tools for %d operations", len(all), len(openapi.Operations))
+	}
+
+	client := listenapi.NewClient(cfg)
+	for _, op := range openapi.Operations {
+		name := op.ToolName
+		if create, ok := overrides[op.ID]; ok {
+			name = create(client).Definition.Name
+		}
+		i, ok := tools[name]
+		if !ok {
+			t.Errorf("%s %s: no tool named %s", op.Method, op.Path, name)
+			continue
+		}
+		schema := all[i].Definition.InputSchema
+		var output map[string]any
+		if err := json.Unmarshal(all[i].Definition.RawOutputSchema, &output); err != nil || output["type"] != "object" {
+			t.Errorf("%s: output schema %s is not an object schema", name, all[i].Definition.RawOutputSchema)
+		}
+
+		specOp := doc.Paths[op.Path][strings.ToLower(op.Method)]
+		want := doc.specParams(specOp)
+		for _, p := range want {
+			prop, ok := schema.Properties[p.Name].(map[string]any)
+			if !ok {
+				t.Errorf("%s: no argument for spec parameter %s", name, p.Name)
+				continue
+			}
+			if got := prop["type"]; got != p.Type {
+				t.Errorf("%s: argument %s has type %v, spec has %s", name, p.Name, got, p.Type)
+			}
+			if got := prop["enum"]; fmt.Sprint(got) != fmt.Sprint(p.Enum) && !(got == nil && p.Enum == nil) {
+				t.Errorf("%s: argument %s has enum %v, spec has %v", name, p.Name, got, p.Enum)
+			}
+			if got := containsString(schema.Required, p.Name); got != p.Required {
+				t.Errorf("%s: argument %s required = %v, spec has %v", name, p.Name, got, p.Required)
+			}
+		}
+		for arg := range schema.Properties {
+			if _, paged := pagedTools[op.ID]; paged && arg == common.PagesArgument {
+				continue
+			}
+			if !containsString(paramNames(want), arg) {
+				t.Errorf("%s: argument %s is not a parameter in the spec", name, arg)
+			}
+		}
+	}
+}
+
+// TestModelsRoundTrip serves, for every operation, a response that sets
+// every property of its spec schema to a non-zero value, decodes it through
+// the operation's typed client method and checks that encoding the model,
+// not the raw response, gives the same document back.
+func TestModelsRoundTrip(t *testing.T) {
+	doc := loadSpec(t)
+	for _, op := range openapi.Operations {
+		specOp := doc.Paths[op.Path][strings.ToLower(op.Method)]
+		content, ok := specOp.Responses["200"].Content["application/json"]
+		if !ok {
+			continue
+		}
+		t.Run(op.ToolName, func(t *testing.T) {
+			sample, err := json.Marshal(doc.sample(content.Schema, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(sample)
+			}))
+			defer upstream.Close()
+
+			path := make(map[string]string)
+			for _, p := range op.Params {
+				if p.In == openapi.InPath {
+					path[p.Name] = "x"
+				}
+			}
+			client := listenapi.NewClient(testConfig(upstream.URL))
+			result, err := op.Call(context.Background(), client, path, nil, nil)
+			if err != nil {
+				t.Fatalf("response does not decode into the model: %v", err)
+			}
+			model, ok := result.(interface{ Model() any })
+			if !ok {
+				t.Fatalf("%T has no model", result)
+			}
+			encoded, err := json.Marshal(model.Model())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want, got any
+			json.Unmarshal(sample, &want)
+			json.Unmarshal(encoded, &got)
+			for _, diff := range jsonDiff("$", want, got) {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+// sample returns a value for s in which every property is present and no
+// value is a zero value, so that omitempty cannot hide a dropped field.
+func (d *contractSpec) sample(s *specSchema, depth int) any {
+	s = d.resolve(s)
+	if s == nil || depth > 8 {
+		return nil
+	}
+	switch {
+	case len(s.AllOf) > 0:
+		merged := map[string]any{}
+		for _, part := range s.AllOf {
+			if obj, ok := d.sample(part, depth).(map[string]any); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	case len(s.OneOf) > 0:
+		return d.sample(s.OneOf[0], depth)
+	case len(s.AnyOf) > 0:
+		return d.sample(s.AnyOf[0], depth)
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+	switch d.typeName(s) {
+	case "object":
+		obj := make(map[string]any, len(s.Properties))
+		for name, prop := range s.Properties {
+			obj[name] = d.sample(prop, depth+1)
+		}
+		return obj
+	case "array":
+		return []any{d.sample(s.Items, depth+1)}
+	case "integer":
+		return 7
+	case "number":
+		return 7.5
+	case "boolean":
+		return true
+	default:
+		return "value"
+	}
+}
+
+// jsonDiff describes how got differs from want, two decoded JSON documents.
+func jsonDiff(at string, want, got any) []string {
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %v, want an object", at, got)}
+		}
+		var diffs []string
+		for _, k := range sortedKeys(w) {
+			if _, ok := g[k]; !ok {
+				diffs = append(diffs, fmt.Sprintf("%s.%s: dropped by the model", at, k))
+				continue
+			}
+			diffs = append(diffs, jsonDiff(at+"."+k, w[k], g[k])...)
+		}
+		for _, k := range sortedKeys(g) {
+			if _, ok := w[k]; !ok {
+				diffs = append(diffs, fmt.Sprintf("%s.%s: in the model but not in the spec", at, k))
+			}
+		}
+		return diffs
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(g) != len(w) {
+			return []string{fmt.Sprintf("%s: got %v, want %d items", at, got, len(w))}
+		}
+		var diffs []string
+		for i := range w {
+			diffs = append(diffs, jsonDiff(fmt.Sprintf("%s[%d]", at, i), w[i], g[i])...)
+		}
+		return diffs
+	default:
+		if !reflect.DeepEqual(want, got) {
+			return []string{fmt.Sprintf("%s: got %v, want %v", at, got, want)}
+		}
+		return nil
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,1581 @@
+package main
+
+// End-to-end tests: each starts the MCP server in-process, connects an MCP
+// client over stdio or Streamable HTTP, and calls tools against an httptest
+// fake of the Listen API that records every request it receives.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/mock"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const testAPIKey = "test-key"
+
+// upstreamRequest is a request the fake Listen API received.
+type upstreamRequest struct {
+	Method string
+	Path   string // as sent, with its escapes
+	Query  url.Values
+	Form   url.Values
+	Header http.Header
+}
+
+// fakeUpstream is an httptest fake of the Listen API. Unless a test sets its
+// own handler, it answers with the examples from openapi.yaml.
+type fakeUpstream struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handler  http.Handler
+	requests []upstreamRequest
+}
+
+func newFakeUpstream(t *testing.T) *fakeUpstream {
+	t.Helper()
+	examples, err := mock.NewServer(openapi.Spec, mock.Options{})
+	if err != nil {
+		t.Fatalf("mock.NewServer: %v", err)
+	}
+	f := &fakeUpstream{handler: examples}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+		f.mu.Lock()
+		f.requests = append(f.requests, upstreamRequest{
+			Method: r.Method,
+			Path:   r.URL.EscapedPath(),
+			Query:  r.URL.Query(),
+			Form:   form,
+			Header: r.Header.Clone(),
+		})
+		handler := f.handler
+		f.mu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// respond makes the fake answer every request with status and body.
+func (f *fakeUpstream) respond(status int, body string) {
+	f.handle(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+}
+
+// handle makes the fake answer every request with h.
+func (f *fakeUpstream) handle(h http.Handler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handler = h
+}
+
+// block makes the fake hold every request until the client abandons it.
+// started receives when a request arrives, aborted when it is abandoned.
+func (f *fakeUpstream) block() (started, aborted <-chan struct{}) {
+	start, abort := make(chan struct{}, 16), make(chan struct{}, 16)
+	f.handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start <- struct{}{}
+		<-r.Context().Done()
+		abort <- struct{}{}
+	}))
+	return start, abort
+}
+
+// take returns the requests received so far and forgets them.
+func (f *fakeUpstream) take() []upstreamRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	requests := f.requests
+	f.requests = nil
+	return requests
+}
+
+// testConfig has no cache, retries or rate limit, so that every tool call
+// makes exactly the upstream requests it needs.
+func testConfig(baseURL string) *config.APIConfig {
+	return &config.APIConfig{
+		BaseURL:        baseURL,
+		APIKey:         testAPIKey,
+		Cache:          config.CacheOff,
+		RequestTimeout: 5 * time.Second,
+		CallTimeout:    10 * time.Second,
+	}
+}
+
+// transports are the ways tests connect to the server.
+var transports = []struct {
+	name    string
+	connect func(t *testing.T, cfg *config.APIConfig) *client.Client
+}{
+	{"stdio", connectStdio},
+	{"http", connectHTTP},
+}
+
+// connectStdio serves cfg over an in-process stdio transport.
+func connectStdio(t *testing.T, cfg *config.APIConfig) *client.Client {
+	t.Helper()
+	mcpSrv := createMCPServer(cfg, "STDIO", &server.Hooks{})
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serveStdio(ctx, mcpSrv, stdinR, stdoutW)
+	}()
+
+	c := client.NewClient(transport.NewIO(stdoutR, stdinW, io.NopCloser(strings.NewReader(""))))
+	t.Cleanup(func() {
+		c.Close()
+		cancel()
+		stdinW.Close()
+		stdoutW.Close()
+		<-done
+	})
+	initialize(t, c)
+	return c
+}
+
+// connectHTTP serves HTTP mode and connects to /mcp the way a remote client
+// does: the base URL and API key travel as headers of the session.
+func connectHTTP(t *testing.T, cfg *config.APIConfig) *client.Client {
+	t.Helper()
+	serverCfg := *cfg
+	serverCfg.BaseURL, serverCfg.APIKey = "", ""
+	handler, _, stop := newHTTPHandler(&serverCfg, "HTTP", &http.Server{})
+	srv := httptest.NewServer(handler)
+
+	c, err := client.NewStreamableHttpClient(srv.URL+"/mcp", transport.WithHTTPHeaders(map[string]string{
+		"API_BASE_URL":    cfg.BaseURL,
+		"X-ListenAPI-Key": cfg.APIKey,
+	}), transport.WithContinuousListening())
+	if err != nil {
+		t.Fatalf("NewStreamableHttpClient: %v", err)
+	}
+	t.Cleanup(func() {
+		c.Close()
+		srv.Close()
+		stop()
+	})
+	initialize(t, c)
+	return c
+}
+
+func initialize(t *testing.T, c *client.Client) {
+	t.Helper()
+	// Start's context bounds the client's notification stream, not just the
+	// call
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req := mcp.InitializeRequest{}
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	req.Params.ClientInfo = mcp.Implementation{Name: "e2e", Version: "1.0"}
+	if _, err := c.Initialize(ctx, req); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+}
+
+func callTool(t *testing.T, c *client.Client, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	res, err := c.CallTool(ctx, req)
+	if err != nil {
+		t.Fatalf("CallTool %s: %v", name, err)
+	}
+	return res
+}
+
+func resultText(res *mcp.CallToolResult) string {
+	var b strings.Builder
+	for _, content := range res.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			b.WriteString(text.Text)
+		}
+	}
+	return b.String()
+}
+
+// toolCase is a tool call and the one upstream request it must make.
+type toolCase struct {
+	tool   string
+	args   map[string]any
+	method string
+	path   string
+	query  url.Values
+	form   url.Values
+	// wantText is a fragment the result must contain, if not empty
+	wantText string
+}
+
+// toolCases covers every tool in registry.go; TestEveryToolIsCovered fails
+// when a tool is added without a case.
+var toolCases = []toolCase{
+	{
+		tool:   "get_best_podcasts",
+		args:   map[string]any{"genre_id": "93", "page": 2, "region": "us", "publisher_region": "gb", "language": "English", "sort": "listen_score", "safe_mode": 0},
+		method: "GET", path: "/best_podcasts",
+		query: url.Values{"genre_id": {"93"}, "page": {"2"}, "region": {"us"}, "publisher_region": {"gb"}, "language": {"English"}, "sort": {"listen_score"}, "safe_mode": {"0"}},
+	},
+	{
+		tool:   "get_curated_podcasts",
+		args:   map[string]any{"page": 3},
+		method: "GET", path: "/curated_podcasts",
+		query: url.Values{"page": {"3"}},
+	},
+	{
+		tool:   "get_curated_podcasts_id",
+		args:   map[string]any{"id": "SDFKduyJ47r"},
+		method: "GET", path: "/curated_podcasts/SDFKduyJ47r",
+		wantText: `"id": "SDFKduyJ47r"`,
+	},
+	{
+		tool:   "get_episodes_batch",
+		args:   map[string]any{"ids": "e1,e2"},
+		method: "POST", path: "/episodes",
+		form:     url.Values{"ids": {"e1,e2"}},
+		wantText: `"id": "e2"`,
+	},
+	{
+		tool:   "get_episodes_id",
+		args:   map[string]any{"id": "ep1", "show_transcript": 1},
+		method: "GET", path: "/episodes/ep1",
+		query:    url.Values{"show_transcript": {"1"}},
+		wantText: `"id": "ep1"`,
+	},
+	{
+		tool:   "get_episodes_id_recommendations",
+		args:   map[string]any{"id": "ep1", "safe_mode": 1},
+		method: "GET", path: "/episodes/ep1/recommendations",
+		query: url.Values{"safe_mode": {"1"}},
+	},
+	{
+		tool:   "get_genres",
+		args:   map[string]any{"top_level_only": 1},
+		method: "GET", path: "/genres",
+		query:    url.Values{"top_level_only": {"1"}},
+		wantText: `"genres"`,
+	},
+	{
+		tool:   "get_just_listen",
+		args:   map[string]any{},
+		method: "GET", path: "/just_listen",
+	},
+	{
+		tool:   "get_languages",
+		args:   map[string]any{},
+		method: "GET", path: "/languages",
+		wantText: `"languages"`,
+	},
+	{
+		tool:   "get_playlists",
+		args:   map[string]any{"sort": "recent_added_first", "page": 1},
+		method: "GET", path: "/playlists",
+		query: url.Values{"sort": {"recent_added_first"}, "page": {"1"}},
+	},
+	{
+		tool:   "get_playlists_id",
+		args:   map[string]any{"id": "m1pe7z60bsw", "type": "episode_list", "last_timestamp_ms": 1600000000000, "sort": "recent_added_first"},
+		method: "GET", path: "/playlists/m1pe7z60bsw",
+		query: url.Values{"type": {"episode_list"}, "last_timestamp_ms": {"1600000000000"}, "sort": {"recent_added_first"}},
+	},
+	{
+		tool:   "get_podcasts_batch",
+		args:   map[string]any{"ids": "p1,p2", "show_latest_episodes": 1},
+		method: "POST", path: "/podcasts",
+		form:     url.Values{"ids": {"p1,p2"}, "show_latest_episodes": {"1"}},
+		wantText: `"id": "p2"`,
+	},
+	{
+		tool:   "get_podcasts_domains_domain_name",
+		args:   map[string]any{"domain_name": "nytimes.com", "page": 2},
+		method: "GET", path: "/podcasts/domains/nytimes.com",
+		query: url.Values{"page": {"2"}},
+	},
+	{
+		tool:   "submit_podcast",
+		args:   map[string]any{"rss": "https://example.com/feed.xml", "email": "host@example.com"},
+		method: "POST", path: "/podcasts/submit",
+		form: url.Values{"rss": {"https://example.com/feed.xml"}, "email": {"host@example.com"}},
+	},
+	{
+		tool:   "delete_podcasts_id",
+		args:   map[string]any{"id": "p1", "reason": "duplicate"},
+		method: "DELETE", path: "/podcasts/p1",
+		query: url.Values{"reason": {"duplicate"}},
+	},
+	{
+		tool:   "get_podcasts_id",
+		args:   map[string]any{"id": "p1", "next_episode_pub_date": 1479154463000, "sort": "recent_first"},
+		method: "GET", path: "/podcasts/p1",
+		query:    url.Values{"next_episode_pub_date": {"1479154463000"}, "sort": {"recent_first"}},
+		wantText: `"id": "p1"`,
+	},
+	{
+		tool:   "get_podcasts_id_audience",
+		args:   map[string]any{"id": "p1"},
+		method: "GET", path: "/podcasts/p1/audience",
+	},
+	{
+		tool:   "get_podcasts_id_recommendations",
+		args:   map[string]any{"id": "p1", "safe_mode": 0},
+		method: "GET", path: "/podcasts/p1/recommendations",
+		query: url.Values{"safe_mode": {"0"}},
+	},
+	{
+		tool:   "get_regions",
+		args:   map[string]any{},
+		method: "GET", path: "/regions",
+	},
+	{
+		tool:   "get_related_searches",
+		args:   map[string]any{"q": "star wars"},
+		method: "GET", path: "/related_searches",
+		query: url.Values{"q": {"star wars"}},
+	},
+	{
+		tool:   "get_search",
+		args:   map[string]any{"q": "star wars", "type": "podcast", "offset": 10, "len_min": 5, "genre_ids": "68,82", "published_after": 1390190241000, "page_size": 5},
+		method: "GET", path: "/search",
+		query: url.Values{"q": {"star wars"}, "type": {"podcast"}, "offset": {"10"}, "len_min": {"5"}, "genre_ids": {"68,82"}, "published_after": {"1390190241000"}, "page_size": {"5"}},
+	},
+	{
+		tool:   "get_spellcheck",
+		args:   map[string]any{"q": "evergrand stok"},
+		method: "GET", path: "/spellcheck",
+		query: url.Values{"q": {"evergrand stok"}},
+	},
+	{
+		tool:   "get_trending_searches",
+		args:   map[string]any{},
+		method: "GET", path: "/trending_searches",
+	},
+	{
+		tool:   "get_typeahead",
+		args:   map[string]any{"q": "star", "show_podcasts": 1, "show_genres": 1, "safe_mode": 0},
+		method: "GET", path: "/typeahead",
+		query: url.Values{"q": {"star"}, "show_podcasts": {"1"}, "show_genres": {"1"}, "safe_mode": {"0"}},
+	},
+}
+
+func TestEveryToolIsCovered(t *testing.T) {
+	covered := make(map[string]bool, len(toolCases))
+	for _, tc := range toolCases {
+		covered[tc.tool] = true
+	}
+	var missing []string
+	for _, tool := range GetAll(testConfig("")) {
+		if !covered[tool.Definition.Name] {
+			missing = append(missing, tool.Definition.Name)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("tools without a case in toolCases: %s", strings.Join(missing, ", "))
+	}
+}
+
+func TestTools(t *testing.T) {
+	outputSchemas := make(map[string]map[string]any)
+	for _, tool := range GetAll(testConfig("")) {
+		var schema map[string]any
+		if err := json.Unmarshal(tool.Definition.RawOutputSchema, &schema); err != nil {
+			t.Fatalf("%s: output schema: %v", tool.Definition.Name, err)
+		}
+		outputSchemas[tool.Definition.Name] = schema
+	}
+
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			c := tr.connect(t, testConfig(upstream.URL))
+
+			for _, tc := range toolCases {
+				t.Run(tc.tool, func(t *testing.T) {
+					res := callTool(t, c, tc.tool, tc.args)
+					text := resultText(res)
+					if res.IsError {
+						t.Fatalf("tool error: %s", text)
+					}
+					if !json.Valid([]byte(text)) {
+						t.Errorf("result is not JSON: %s", text)
+					}
+					if tc.wantText != "" && !strings.Contains(text, tc.wantText) {
+						t.Errorf("result does not contain %s:\n%s", tc.wantText, text)
+					}
+					var textDoc any
+					json.Unmarshal([]byte(text), &textDoc)
+					if !reflect.DeepEqual(res.StructuredContent, textDoc) {
+						t.Errorf("structured content %v differs from the text", res.StructuredContent)
+					}
+					for _, problem := range conforms(outputSchemas[tc.tool], res.StructuredContent, "$") {
+						t.Errorf("structured content does not match the output schema: %s", problem)
+					}
+
+					requests := upstream.take()
+					if len(requests) != 1 {
+						t.Fatalf("got %d upstream requests, want 1: %+v", len(requests), requests)
+					}
+					got := requests[0]
+					if got.Method != tc.method || got.Path != tc.path {
+						t.Errorf("upstream request = %s %s, want %s %s", got.Method, got.Path, tc.method, tc.path)
+					}
+					if got.Query.Encode() != tc.query.Encode() {
+						t.Errorf("query = %q, want %q", got.Query.Encode(), tc.query.Encode())
+					}
+					if got.Form.Encode() != tc.form.Encode() {
+						t.Errorf("form = %q, want %q", got.Form.Encode(), tc.form.Encode())
+					}
+					if key := got.Header.Get("X-ListenAPI-Key"); key != testAPIKey {
+						t.Errorf("X-ListenAPI-Key = %q, want %q", key, testAPIKey)
+					}
+					if accept := got.Header.Get("Accept"); accept != "application/json" {
+						t.Errorf("Accept = %q, want application/json", accept)
+					}
+					wantContentType := ""
+					if tc.form != nil {
+						wantContentType = "application/x-www-form-urlencoded"
+					}
+					if ct := got.Header.Get("Content-Type"); ct != wantContentType {
+						t.Errorf("Content-Type = %q, want %q", ct, wantContentType)
+					}
+				})
+			}
+		})
+	}
+}
+
+// conforms reports where v breaks schema. It understands the part of JSON
+// Schema that output schemas use: type, properties and items.
+func conforms(schema map[string]any, v any, at string) []string {
+	if types, ok := schema["type"]; ok && !hasJSONType(types, v) {
+		return []string{fmt.Sprintf("%s: %v is not of type %v", at, v, types)}
+	}
+	var problems []string
+	switch v := v.(type) {
+	case map[string]any:
+		props, _ := schema["properties"].(map[string]any)
+		for name, val := range v {
+			if prop, ok := props[name].(map[string]any); ok {
+				problems = append(problems, conforms(prop, val, at+"."+name)...)
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				problems = append(problems, conforms(items, item, fmt.Sprintf("%s[%d]", at, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+func hasJSONType(types any, v any) bool {
+	var names []any
+	switch t := types.(type) {
+	case string:
+		names = []any{t}
+	case []any:
+		names = t
+	}
+	for _, name := range names {
+		switch name {
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]any); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]any); ok {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "number":
+			if _, ok := v.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := v.(float64); ok && f == float64(int64(f)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestUpstreamErrorStatuses(t *testing.T) {
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			c := tr.connect(t, testConfig(upstream.URL))
+
+			for _, status := range []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError} {
+				t.Run(http.StatusText(status), func(t *testing.T) {
+					body := `{"error":"` + http.StatusText(status) + `"}`
+					upstream.respond(status, body)
+					res := callTool(t, c, "get_podcasts_id", map[string]any{"id": "p1"})
+					if !res.IsError {
+						t.Fatalf("IsError = false, want true")
+					}
+					if got, want := resultText(res), "API error: "+body; got != want {
+						t.Errorf("result = %q, want %q", got, want)
+					}
+					if n := len(upstream.take()); n != 1 {
+						t.Errorf("got %d upstream requests, want 1", n)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestUndecodableResponseIsAnError(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"not JSON", `<html>not json</html>`, "failed to decode response: response is not JSON: <html>not json</html>"},
+		{"not an object", `["Arts","Business"]`, "Response is not a JSON object: [\n  \"Arts\",\n  \"Business\"\n]"},
+	}
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			c := tr.connect(t, testConfig(upstream.URL))
+
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					upstream.respond(http.StatusOK, tc.body)
+					res := callTool(t, c, "get_genres", map[string]any{})
+					if !res.IsError {
+						t.Fatalf("IsError = false for %q, want true", resultText(res))
+					}
+					if got := resultText(res); got != tc.want {
+						t.Errorf("result = %q, want %q", got, tc.want)
+					}
+					if res.StructuredContent != nil {
+						t.Errorf("structured content %v, want none outside the output schema", res.StructuredContent)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestResponsesAreLossless(t *testing.T) {
+	cases := []struct {
+		name string
+		tool string
+		args map[string]any
+		body string
+		want string // the body when empty
+	}{
+		{
+			name: "zero, false, null and unknown fields",
+			tool: "get_podcasts_id", args: map[string]any{"id": "p1"},
+			body: `{"id":"p1","listen_score":0,"explicit_content":false,"rss":null,"is_claimed":false,"brand_new_field":{"nested":[1,2]}}`,
+		},
+		{
+			name: "field that does not fit the model",
+			tool: "get_podcasts_id", args: map[string]any{"id": "p1"},
+			body: `{"id":"p1","total_episodes":"many","title":"Star Wars 7x7"}`,
+		},
+		{
+			name: "large integers",
+			tool: "get_episodes_id", args: map[string]any{"id": "e1"},
+			body: `{"id":"e1","pub_date_ms":1479110402123,"audio_length_sec":0}`,
+		},
+		{
+			name: "merged batch",
+			tool: "get_episodes_batch", args: map[string]any{"ids": "e1,e2,e3,e4,e5,e6,e7,e8,e9,e10,e11"},
+			body: `{"episodes":[{"id":"e","explicit_content":false,"extra":null}],"note":"kept"}`,
+			want: `{"episodes":[{"id":"e","explicit_content":false,"extra":null},{"id":"e","explicit_content":false,"extra":null}],"note":"kept"}`,
+		},
+	}
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			c := tr.connect(t, testConfig(upstream.URL))
+
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					upstream.respond(http.StatusOK, tc.body)
+					res := callTool(t, c, tc.tool, tc.args)
+					if res.IsError {
+						t.Fatalf("tool error: %s", resultText(res))
+					}
+					want := tc.want
+					if want == "" {
+						want = tc.body
+					}
+					var got, wantDoc any
+					if err := json.Unmarshal([]byte(resultText(res)), &got); err != nil {
+						t.Fatalf("result is not JSON: %v", err)
+					}
+					json.Unmarshal([]byte(want), &wantDoc)
+					for _, diff := range jsonDiff("$", wantDoc, got) {
+						t.Error(diff)
+					}
+					upstream.take()
+				})
+			}
+		})
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	cases := []struct {
+		name string
+		tool string
+		args map[string]any
+		want string
+	}{
+		{"missing path", "get_podcasts_id", map[string]any{}, "Invalid arguments: id is required"},
+		{"empty path", "get_podcasts_domains_domain_name", map[string]any{"domain_name": "", "page": 2}, "Invalid arguments: domain_name must not be empty"},
+		{"missing query", "get_search", map[string]any{"type": "episode"}, "Invalid arguments: q is required"},
+		{"missing form", "get_episodes_batch", map[string]any{}, "Invalid arguments: ids is required"},
+		{"no batch lookups", "get_podcasts_batch", map[string]any{"show_latest_episodes": 1}, "Invalid arguments: ids is required unless rsses, itunes_ids or spotify_ids is given"},
+		{"missing rss", "submit_podcast", map[string]any{"email": "host@example.com"}, "Invalid arguments: rss is required"},
+		{"bad rss and email", "submit_podcast", map[string]any{"rss": "feed.xml", "email": "nobody"}, "Invalid arguments: rss must be an absolute http or https url; email must be a valid email address"},
+		{"enum", "get_search", map[string]any{"q": "star", "type": "movie"}, "Invalid arguments: type must be one of episode, podcast, curated"},
+		{"integer enum", "get_typeahead", map[string]any{"q": "star", "show_podcasts": 2}, "Invalid arguments: show_podcasts must be one of 0, 1"},
+		{"fraction", "get_best_podcasts", map[string]any{"page": 1.5}, "Invalid arguments: page must be an integer"},
+		{"not a number", "get_best_podcasts", map[string]any{"page": "two"}, "Invalid arguments: page must be an integer"},
+		{"range", "get_search", map[string]any{"q": "star", "page_size": 20}, "Invalid arguments: page_size must be between 1 and 10"},
+		{"lower bound", "get_curated_podcasts", map[string]any{"page": 0}, "Invalid arguments: page must be at least 1"},
+		{"pages", "get_search", map[string]any{"q": "star", "pages": 11}, "Invalid arguments: pages must be between 1 and 10"},
+		{"timestamp", "get_search", map[string]any{"q": "star", "published_after": "last week"}, "Invalid arguments: published_after must be a timestamp in milliseconds or an RFC 3339 time"},
+		{"published order", "get_search", map[string]any{"q": "star", "published_after": 1600000000000, "published_before": 1500000000000}, "Invalid arguments: published_before must be greater than published_after"},
+		{"length order", "get_search", map[string]any{"q": "star", "len_min": 30, "len_max": 10}, "Invalid arguments: len_max must not be less than len_min"},
+		{"several", "get_search", map[string]any{"page_size": 0, "sort_by_date": 3}, "Invalid arguments: q is required; sort_by_date must be one of 0, 1; page_size must be between 1 and 10"},
+	}
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			c := tr.connect(t, testConfig(upstream.URL))
+
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					res := callTool(t, c, tc.tool, tc.args)
+					if !res.IsError {
+						t.Fatalf("IsError = false, want true")
+					}
+					if got := resultText(res); got != tc.want {
+						t.Errorf("result = %q, want %q", got, tc.want)
+					}
+					structured, _ := res.StructuredContent.(map[string]any)
+					if errs, _ := structured["errors"].([]any); len(errs) == 0 {
+						t.Errorf("structured content = %v, want the list of errors", res.StructuredContent)
+					}
+					if requests := upstream.take(); len(requests) != 0 {
+						t.Errorf("got %d upstream requests, want none", len(requests))
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestArgumentBinding(t *testing.T) {
+	cases := []struct {
+		name  string
+		tool  string
+		args  map[string]any
+		path  string
+		query url.Values
+		form  url.Values
+	}{
+		{
+			name: "query escaping",
+			tool: "get_search", args: map[string]any{"q": "rock & roll #1?"},
+			path: "/search", query: url.Values{"q": {"rock & roll #1?"}},
+		},
+		{
+			name: "path escaping",
+			tool: "get_podcasts_id", args: map[string]any{"id": "a/b?c"},
+			path: "/podcasts/a%2Fb%3Fc",
+		},
+		{
+			name: "large integer",
+			tool: "get_podcasts_id", args: map[string]any{"id": "p1", "next_episode_pub_date": 1e12},
+			path: "/podcasts/p1", query: url.Values{"next_episode_pub_date": {"1000000000000"}},
+		},
+		{
+			name: "integer as string",
+			tool: "get_best_podcasts", args: map[string]any{"page": "3", "genre_id": 93},
+			path: "/best_podcasts", query: url.Values{"page": {"3"}, "genre_id": {"93"}},
+		},
+		{
+			name: "timestamp as date",
+			tool: "get_search", args: map[string]any{"q": "star", "published_after": "2020-01-01", "published_before": "2020-01-02T00:00:00Z"},
+			path: "/search", query: url.Values{"q": {"star"}, "published_after": {"1577836800000"}, "published_before": {"1577923200000"}},
+		},
+		{
+			name: "list of ids",
+			tool: "get_episodes_batch", args: map[string]any{"ids": []any{"e1", "e2"}},
+			path: "/episodes", form: url.Values{"ids": {"e1,e2"}},
+		},
+		{
+			name: "empty optional argument",
+			tool: "get_best_podcasts", args: map[string]any{"sort": "", "region": "us"},
+			path: "/best_podcasts", query: url.Values{"region": {"us"}},
+		},
+	}
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			c := tr.connect(t, testConfig(upstream.URL))
+
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					res := callTool(t, c, tc.tool, tc.args)
+					if res.IsError {
+						t.Fatalf("tool error: %s", resultText(res))
+					}
+					requests := upstream.take()
+					if len(requests) != 1 {
+						t.Fatalf("got %d upstream requests, want 1", len(requests))
+					}
+					got := requests[0]
+					if got.Path != tc.path {
+						t.Errorf("path = %s, want %s", got.Path, tc.path)
+					}
+					if got.Query.Encode() != tc.query.Encode() {
+						t.Errorf("query = %q, want %q", got.Query.Encode(), tc.query.Encode())
+					}
+					if got.Form.Encode() != tc.form.Encode() {
+						t.Errorf("form = %q, want %q", got.Form.Encode(), tc.form.Encode())
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestUnreachableUpstream(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cfg := testConfig(upstream.URL)
+	upstream.Close()
+
+	c := connectStdio(t, cfg)
+	res := callTool(t, c, "get_genres", map[string]any{})
+	if !res.IsError {
+		t.Fatalf("IsError = false, want true")
+	}
+	if text := resultText(res); !strings.HasPrefix(text, "Request failed: ") {
+		t.Errorf("result = %q, want a Request failed error", text)
+	}
+}
+
+func readResource(t *testing.T, c *client.Client, uri string) (*mcp.ReadResourceResult, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = uri
+	return c.ReadResource(ctx, req)
+}
+
+func TestResources(t *testing.T) {
+	cases := []struct {
+		uri  string
+		path string
+	}{
+		{"listennotes://podcast/p1", "/podcasts/p1"},
+		{"listennotes://podcast/a%2Fb%3Fc", "/podcasts/a%2Fb%3Fc"},
+		{"listennotes://episode/e1", "/episodes/e1"},
+		{"listennotes://playlist/pl1", "/playlists/pl1"},
+		{"listennotes://curated/c1", "/curated_podcasts/c1"},
+		{"listennotes://genres", "/genres"},
+		{"listennotes://languages", "/languages"},
+		{"listennotes://regions", "/regions"},
+	}
+
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			c := tr.connect(t, testConfig(upstream.URL))
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			templates, err := c.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+			if err != nil {
+				t.Fatalf("ListResourceTemplates: %v", err)
+			}
+			var templateURIs []string
+			for _, tmpl := range templates.ResourceTemplates {
+				templateURIs = append(templateURIs, tmpl.URITemplate.Raw())
+			}
+			sort.Strings(templateURIs)
+			if got, want := strings.Join(templateURIs, " "), "listennotes://curated/{id} listennotes://episode/{id} listennotes://playlist/{id} listennotes://podcast/{id}"; got != want {
+				t.Errorf("templates = %s, want %s", got, want)
+			}
+
+			resources, err := c.ListResources(ctx, mcp.ListResourcesRequest{})
+			if err != nil {
+				t.Fatalf("ListResources: %v", err)
+			}
+			var resourceURIs []string
+			for _, res := range resources.Resources {
+				resourceURIs = append(resourceURIs, res.URI)
+			}
+			sort.Strings(resourceURIs)
+			if got, want := strings.Join(resourceURIs, " "), "listennotes://genres listennotes://languages listennotes://regions"; got != want {
+				t.Errorf("resources = %s, want %s", got, want)
+			}
+
+			const body = `{"id":"x","extra_field":{"kept":true}}`
+			upstream.respond(http.StatusOK, body)
+			for _, tc := range cases {
+				t.Run(tc.uri, func(t *testing.T) {
+					res, err := readResource(t, c, tc.uri)
+					if err != nil {
+						t.Fatalf("ReadResource: %v", err)
+					}
+					if len(res.Contents) != 1 {
+						t.Fatalf("got %d contents, want 1", len(res.Contents))
+					}
+					text, ok := res.Contents[0].(mcp.TextResourceContents)
+					if !ok {
+						t.Fatalf("contents are %T, want text", res.Contents[0])
+					}
+					if text.URI != tc.uri || text.MIMEType != "application/json" {
+						t.Errorf("contents are %s as %s, want %s as application/json", text.URI, text.MIMEType, tc.uri)
+					}
+					var got, want any
+					json.Unmarshal([]byte(body), &want)
+					if err := json.Unmarshal([]byte(text.Text), &got); err != nil {
+						t.Fatalf("contents are not JSON: %v", err)
+					}
+					for _, d := range jsonDiff("$", want, got) {
+						t.Error(d)
+					}
+
+					requests := upstream.take()
+					if len(requests) != 1 {
+						t.Fatalf("got %d upstream requests, want 1", len(requests))
+					}
+					if got := requests[0].Path; got != tc.path {
+						t.Errorf("path = %s, want %s", got, tc.path)
+					}
+					if got := requests[0].Header.Get("X-ListenAPI-Key"); got != testAPIKey {
+						t.Errorf("X-ListenAPI-Key = %q, want %q", got, testAPIKey)
+					}
+				})
+			}
+
+			t.Run("upstream error", func(t *testing.T) {
+				upstream.respond(http.StatusNotFound, `{"error":"Not Found"}`)
+				_, err := readResource(t, c, "listennotes://podcast/missing")
+				if err == nil || !strings.Contains(err.Error(), `API error: {"error":"Not Found"}`) {
+					t.Errorf("ReadResource error = %v, want the API error", err)
+				}
+			})
+		})
+	}
+}
+
+// connectSSE serves SSE mode and connects over the legacy SSE transport.
+func connectSSE(t *testing.T, cfg *config.APIConfig) *client.Client {
+	t.Helper()
+	serverCfg := *cfg
+	serverCfg.BaseURL, serverCfg.APIKey = "", ""
+	httpServer := &http.Server{}
+	handler, _, stop := newHTTPHandler(&serverCfg, "SSE", httpServer)
+	srv := httptest.NewServer(handler)
+
+	c, err := client.NewSSEMCPClient(srv.URL+"/sse", transport.WithHeaders(map[string]string{
+		"API_BASE_URL":    cfg.BaseURL,
+		"X-ListenAPI-Key": cfg.APIKey,
+	}))
+	if err != nil {
+		t.Fatalf("NewSSEMCPClient: %v", err)
+	}
+	t.Cleanup(func() {
+		c.Close()
+		srv.CloseClientConnections()
+		srv.Close()
+		stop()
+	})
+	initialize(t, c)
+	return c
+}
+
+// allTransports are transports and the legacy SSE transport, for features
+// that reach the server through our own handling of each transport.
+func allTransports() []struct {
+	name    string
+	connect func(t *testing.T, cfg *config.APIConfig) *client.Client
+} {
+	return append(transports[:len(transports):len(transports)], struct {
+		name    string
+		connect func(t *testing.T, cfg *config.APIConfig) *client.Client
+	}{"sse", connectSSE})
+}
+
+func TestResourceSubscriptions(t *testing.T) {
+	podcast := func(pubDate int, episode string) string {
+		return fmt.Sprintf(`{"id":"p1","title":"Show","latest_pub_date_ms":%d,"latest_episode_id":%q}`, pubDate, episode)
+	}
+	for _, tr := range allTransports() {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			cfg := testConfig(upstream.URL)
+			cfg.SubscriptionPollInterval = 20 * time.Millisecond
+			c := tr.connect(t, cfg)
+
+			updates := make(chan string, 16)
+			c.OnNotification(func(n mcp.JSONRPCNotification) {
+				if n.Method == mcp.MethodNotificationResourceUpdated {
+					uri, _ := n.Params.AdditionalFields["uri"].(string)
+					updates <- uri
+				}
+			})
+			expectNoUpdate := func() {
+				t.Helper()
+				select {
+				case uri := <-updates:
+					t.Fatalf("got an update of %s, want none", uri)
+				case <-time.After(200 * time.Millisecond):
+				}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			subscribe := func(uri string) error {
+				req := mcp.SubscribeRequest{}
+				req.Params.URI = uri
+				return c.Subscribe(ctx, req)
+			}
+
+			upstream.respond(http.StatusOK, podcast(1, "e1"))
+			if err := subscribe("listennotes://podcast/p1"); err != nil {
+				t.Fatalf("Subscribe: %v", err)
+			}
+			if requests := upstream.take(); len(requests) == 0 || requests[0].Path != "/podcasts/p1" {
+				t.Fatalf("subscribing made requests %v, want a fetch of /podcasts/p1", requests)
+			}
+			expectNoUpdate()
+
+			upstream.respond(http.StatusOK, podcast(2, "e2"))
+			select {
+			case uri := <-updates:
+				if uri != "listennotes://podcast/p1" {
+					t.Errorf("update of %s, want listennotes://podcast/p1", uri)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no update after a new episode")
+			}
+			for _, r := range upstream.take() {
+				if got := r.Header.Get("X-ListenAPI-Key"); got != testAPIKey {
+					t.Errorf("poll sent X-ListenAPI-Key %q, want %q", got, testAPIKey)
+				}
+			}
+
+			req := mcp.UnsubscribeRequest{}
+			req.Params.URI = "listennotes://podcast/p1"
+			if err := c.Unsubscribe(ctx, req); err != nil {
+				t.Fatalf("Unsubscribe: %v", err)
+			}
+			time.Sleep(50 * time.Millisecond)
+			upstream.take()
+			upstream.respond(http.StatusOK, podcast(3, "e3"))
+			expectNoUpdate()
+			if n := len(upstream.take()); n != 0 {
+				t.Errorf("got %d polls after unsubscribing, want 0", n)
+			}
+
+			if err := subscribe("listennotes://genres"); err == nil || !strings.Contains(err.Error(), "only podcasts can be subscribed to") {
+				t.Errorf("Subscribe to genres: error %v, want it refused", err)
+			}
+			upstream.respond(http.StatusNotFound, `{"error":"Not Found"}`)
+			if err := subscribe("listennotes://podcast/missing"); err == nil || !strings.Contains(err.Error(), "API error") {
+				t.Errorf("Subscribe to a missing podcast: error %v, want the API error", err)
+			}
+		})
+	}
+}
+
+func TestExtraMethodCancellation(t *testing.T) {
+	requests := []struct {
+		method string
+		send   func(ctx context.Context, c *client.Client) error
+	}{
+		{"resources/subscribe", func(ctx context.Context, c *client.Client) error {
+			req := mcp.SubscribeRequest{}
+			req.Params.URI = "listennotes://podcast/p1"
+			return c.Subscribe(ctx, req)
+		}},
+		{"completion/complete", func(ctx context.Context, c *client.Client) error {
+			req := mcp.CompleteRequest{}
+			req.Params.Ref = mcp.PromptReference{Type: "ref/prompt", Name: "competitive_landscape"}
+			req.Params.Argument.Name = "genre_id"
+			req.Params.Argument.Value = "tech"
+			_, err := c.Complete(ctx, req)
+			return err
+		}},
+	}
+	for _, tr := range allTransports() {
+		t.Run(tr.name, func(t *testing.T) {
+			for _, r := range requests {
+				t.Run(r.method, func(t *testing.T) {
+					upstream := newFakeUpstream(t)
+					started, aborted := upstream.block()
+					c := tr.connect(t, testConfig(upstream.URL))
+
+					answered := make(chan error, 1)
+					go func() {
+						ctx, stop := context.WithTimeout(context.Background(), 15*time.Second)
+						defer stop()
+						answered <- r.send(ctx, c)
+					}()
+					waitFor(t, started, "the upstream request starts")
+
+					// Other messages are answered while the request waits
+					ctx, stop := context.WithTimeout(context.Background(), 2*time.Second)
+					defer stop()
+					if err := c.Ping(ctx); err != nil {
+						t.Fatalf("Ping while waiting on %s: %v", r.method, err)
+					}
+
+					cancel(t, c, firstCallID)
+					waitFor(t, aborted, "the upstream request is aborted")
+					select {
+					case err := <-answered:
+						if err == nil {
+							t.Errorf("cancelled %s succeeded", r.method)
+						}
+					case <-time.After(5 * time.Second):
+						t.Fatalf("%s did not return after it was cancelled", r.method)
+					}
+				})
+			}
+		})
+	}
+}
+
+func getPrompt(t *testing.T, c *client.Client, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req := mcp.GetPromptRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	return c.GetPrompt(ctx, req)
+}
+
+func TestPrompts(t *testing.T) {
+	cases := []struct {
+		name string
+		args map[string]string
+		want []string // in the rendered message
+	}{
+		{"find_guests", map[string]string{"topic": "climate tech"}, []string{`q="climate tech"`, "get_search", "get_podcasts_id_recommendations"}},
+		{"find_guests", map[string]string{"topic": "ai", "language": "Spanish"}, []string{`language="Spanish"`}},
+		{"find_guests", map[string]string{"topic": "ai", "genre_ids": "93,127"}, []string{`genre_ids="93,127"`}},
+		{"competitive_landscape", map[string]string{"genre_id": "93"}, []string{`genre_id=93, region="us", pages 1 and 2`, "get_best_podcasts", "get_podcasts_id_recommendations"}},
+		{"competitive_landscape", map[string]string{"genre_id": "93", "publisher_region": "gb"}, []string{`region="us" and publisher_region="gb"`}},
+		{"summarize_recent_episodes", map[string]string{"podcast_id": "p1"}, []string{"latest 5 episodes", `id="p1"`, "get_podcasts_id"}},
+		{"summarize_recent_episodes", map[string]string{"podcast_id": "p1", "count": "12"}, []string{"latest 12 episodes"}},
+		{"build_playlist", map[string]string{"theme": "space", "minutes": "60"}, []string{`q="space"`, "about 60 minutes", "get_search", "get_best_podcasts"}},
+	}
+	// Tool names are the only snake_case words the prompts use
+	toolName := regexp.MustCompile(`\b(get|post|delete)_[a-z_]+[a-z]\b`)
+
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			c := tr.connect(t, testConfig(upstream.URL))
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			tools, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+			if err != nil {
+				t.Fatalf("ListTools: %v", err)
+			}
+			known := map[string]bool{}
+			for _, tool := range tools.Tools {
+				known[tool.Name] = true
+			}
+
+			list, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+			if err != nil {
+				t.Fatalf("ListPrompts: %v", err)
+			}
+			var names []string
+			for _, p := range list.Prompts {
+				names = append(names, p.Name)
+			}
+			sort.Strings(names)
+			if got, want := strings.Join(names, " "), "build_playlist competitive_landscape find_guests summarize_recent_episodes"; got != want {
+				t.Errorf("prompts = %s, want %s", got, want)
+			}
+
+			for _, tc := range cases {
+				res, err := getPrompt(t, c, tc.name, tc.args)
+				if err != nil {
+					t.Errorf("GetPrompt(%s, %v): %v", tc.name, tc.args, err)
+					continue
+				}
+				if len(res.Messages) != 1 || res.Messages[0].Role != mcp.RoleUser {
+					t.Fatalf("%s: got messages %+v, want one user message", tc.name, res.Messages)
+				}
+				text, ok := res.Messages[0].Content.(mcp.TextContent)
+				if !ok {
+					t.Fatalf("%s: content is %T, want text", tc.name, res.Messages[0].Content)
+				}
+				for _, want := range tc.want {
+					if !strings.Contains(text.Text, want) {
+						t.Errorf("%s(%v) does not contain %s:\n%s", tc.name, tc.args, want, text.Text)
+					}
+				}
+				for _, name := range toolName.FindAllString(text.Text, -1) {
+					if !known[name] {
+						t.Errorf("%s refers to %s, which is not a tool", tc.name, name)
+					}
+				}
+			}
+
+			for _, bad := range []struct {
+				name string
+				args map[string]string
+				want string
+			}{
+				{"find_guests", map[string]string{}, "topic is required"},
+				{"summarize_recent_episodes", map[string]string{"podcast_id": "p1", "count": "0"}, "count must be an integer between 1 and 30"},
+				{"build_playlist", map[string]string{"theme": "space", "minutes": "lots"}, "minutes must be an integer between 1 and 1440"},
+			} {
+				if _, err := getPrompt(t, c, bad.name, bad.args); err == nil || !strings.Contains(err.Error(), bad.want) {
+					t.Errorf("GetPrompt(%s, %v): error %v, want %q", bad.name, bad.args, err, bad.want)
+				}
+			}
+			if n := len(upstream.take()); n != 0 {
+				t.Errorf("prompts made %d upstream requests, want 0", n)
+			}
+		})
+	}
+}
+
+func complete(t *testing.T, c *client.Client, ref any, arg, value string) (*mcp.CompleteResult, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req := mcp.CompleteRequest{}
+	req.Params.Ref = ref
+	req.Params.Argument.Name = arg
+	req.Params.Argument.Value = value
+	return c.Complete(ctx, req)
+}
+
+// completionUpstream answers the requests completions make with a few
+// genres, languages, regions and podcasts.
+func completionUpstream(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/genres":
+		io.WriteString(w, `{"genres":[{"id":93,"name":"Business","parent_id":67},{"id":127,"name":"Technology","parent_id":67},{"id":67,"name":"Podcasts"},{"id":131,"name":"Tech News","parent_id":127},{"id":143,"name":"Investing","parent_id":93}]}`)
+	case "/languages":
+		io.WriteString(w, `{"languages":["English","Spanish","Swedish"]}`)
+	case "/regions":
+		io.WriteString(w, `{"regions":{"us":"United States","gb":"United Kingdom","se":"Sweden"}}`)
+	case "/typeahead":
+		io.WriteString(w, `{"terms":["startup"],"podcasts":[{"id":"p1","title_original":"Startup Stories"},{"id":"p2","title_original":"How I Built This"}]}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestCompletions(t *testing.T) {
+	prompt := func(name string) mcp.PromptReference { return mcp.PromptReference{Type: "ref/prompt", Name: name} }
+	resource := func(uri string) mcp.ResourceReference { return mcp.ResourceReference{Type: "ref/resource", URI: uri} }
+	cases := []struct {
+		name  string
+		ref   any
+		arg   string
+		value string
+		want  []string
+		path  string // the upstream request it makes, if any
+	}{
+		{"genre by name", prompt("competitive_landscape"), "genre_id", "tech", []string{"127", "131"}, "/genres"},
+		{"genre by word in name", prompt("competitive_landscape"), "genre_id", "news", []string{"131"}, "/genres"},
+		{"genre by id", prompt("competitive_landscape"), "genre_id", "9", []string{"93"}, "/genres"},
+		{"first genre of a list", prompt("find_guests"), "genre_ids", "busi", []string{"93"}, "/genres"},
+		{"last genre of a list", prompt("find_guests"), "genre_ids", "93,tech", []string{"93,127", "93,131"}, "/genres"},
+		{"every language", prompt("find_guests"), "language", "", []string{"English", "Spanish", "Swedish"}, "/languages"},
+		{"language", prompt("build_playlist"), "language", "SW", []string{"Swedish"}, "/languages"},
+		{"region by code before name", prompt("competitive_landscape"), "region", "u", []string{"us", "gb"}, "/regions"},
+		{"region by name", prompt("competitive_landscape"), "region", "swe", []string{"se"}, "/regions"},
+		{"publisher region", prompt("competitive_landscape"), "publisher_region", "united", []string{"gb", "us"}, "/regions"},
+		{"podcast of a prompt", prompt("summarize_recent_episodes"), "podcast_id", "start", []string{"p1", "p2"}, "/typeahead"},
+		{"podcast of a resource", resource("listennotes://podcast/{id}"), "id", "start", []string{"p1", "p2"}, "/typeahead"},
+		{"nothing typed for a podcast", prompt("summarize_recent_episodes"), "podcast_id", " ", []string{}, ""},
+		{"free text", prompt("find_guests"), "topic", "clim", []string{}, ""},
+		{"resource without completion", resource("listennotes://episode/{id}"), "id", "e", []string{}, ""},
+		{"argument the prompt does not have", prompt("find_guests"), "region", "u", []string{}, ""},
+	}
+
+	for _, tr := range allTransports() {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			upstream.handle(http.HandlerFunc(completionUpstream))
+			c := tr.connect(t, testConfig(upstream.URL))
+
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					res, err := complete(t, c, tc.ref, tc.arg, tc.value)
+					if err != nil {
+						t.Fatalf("Complete: %v", err)
+					}
+					if !reflect.DeepEqual(res.Completion.Values, tc.want) {
+						t.Errorf("values %q, want %q", res.Completion.Values, tc.want)
+					}
+					if res.Completion.Total != len(tc.want) || res.Completion.HasMore {
+						t.Errorf("total %d and hasMore %v, want %d and false", res.Completion.Total, res.Completion.HasMore, len(tc.want))
+					}
+					requests := upstream.take()
+					switch {
+					case tc.path == "" && len(requests) > 0:
+						t.Errorf("made requests %v, want none", requests)
+					case tc.path != "" && len(requests) > 1:
+						t.Errorf("made %d requests, want at most one, to %s", len(requests), tc.path)
+					case len(requests) == 1 && requests[0].Path != tc.path:
+						t.Errorf("requested %s, want %s", requests[0].Path, tc.path)
+					}
+				})
+			}
+
+			// Every list was fetched once above, with the API key
+			if _, err := complete(t, c, prompt("competitive_landscape"), "genre_id", "bus"); err != nil {
+				t.Fatalf("Complete: %v", err)
+			}
+			if _, err := complete(t, c, prompt("summarize_recent_episodes"), "podcast_id", "START"); err != nil {
+				t.Fatalf("Complete: %v", err)
+			}
+			if requests := upstream.take(); len(requests) != 0 {
+				t.Errorf("completing again made requests %v, want them cached", requests)
+			}
+
+			for _, tc := range []struct {
+				name string
+				ref  any
+				want string
+			}{
+				{"unknown prompt", prompt("nope"), `unknown prompt "nope"`},
+				{"unknown resource", resource("listennotes://nope/{id}"), `unknown resource "listennotes://nope/{id}"`},
+				{"unknown reference", map[string]string{"type": "ref/tool"}, `unknown reference type "ref/tool"`},
+			} {
+				if _, err := complete(t, c, tc.ref, "id", "x"); err == nil || !strings.Contains(err.Error(), tc.want) {
+					t.Errorf("%s: error %v, want %q", tc.name, err, tc.want)
+				}
+			}
+
+			upstream.respond(http.StatusInternalServerError, `{"error":"down"}`)
+			if _, err := complete(t, c, prompt("summarize_recent_episodes"), "podcast_id", "other"); err == nil || !strings.Contains(err.Error(), "API error") {
+				t.Errorf("Complete with the API down: error %v, want the API error", err)
+			}
+		})
+	}
+}
+
+func TestCompletionsCapability(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	handler, _, stop := newHTTPHandler(testConfig(upstream.URL), "HTTP", &http.Server{})
+	srv := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		srv.Close()
+		stop()
+	})
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Result struct {
+			Capabilities map[string]any `json:"capabilities"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decoding the initialize response: %v", err)
+	}
+	for _, name := range []string{"completions", "tools", "resources", "prompts"} {
+		if _, ok := result.Result.Capabilities[name]; !ok {
+			t.Errorf("capabilities %v lack %s", result.Result.Capabilities, name)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	ids := make([]string, 25)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("e%d", i+1)
+	}
+
+	for _, tr := range allTransports() {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			upstream.respond(http.StatusOK, `{"episodes":[]}`)
+			c := tr.connect(t, testConfig(upstream.URL))
+
+			progress := make(chan map[string]any, 16)
+			c.OnNotification(func(n mcp.JSONRPCNotification) {
+				if n.Method == "notifications/progress" {
+					progress <- n.Params.AdditionalFields
+				}
+			})
+			call := func(name string, args map[string]any, token mcp.ProgressToken) {
+				t.Helper()
+				ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+				defer cancel()
+				req := mcp.CallToolRequest{}
+				req.Params.Name = name
+				req.Params.Arguments = args
+				if token != nil {
+					req.Params.Meta = &mcp.Meta{ProgressToken: token}
+				}
+				res, err := c.CallTool(ctx, req)
+				if err != nil {
+					t.Fatalf("CallTool %s: %v", name, err)
+				}
+				if res.IsError {
+					t.Fatalf("CallTool %s failed: %s", name, resultText(res))
+				}
+			}
+
+			call("get_episodes_batch", map[string]any{"ids": strings.Join(ids, ",")}, "batch-1")
+			// Notifications may arrive after the result; the last one
+			// reports every lookup
+			last := 0.0
+			for last < 25 {
+				select {
+				case p := <-progress:
+					if p["progressToken"] != "batch-1" {
+						t.Errorf("progress for token %v, want batch-1", p["progressToken"])
+					}
+					got, _ := p["progress"].(float64)
+					if got <= last {
+						t.Errorf("progress went from %v to %v, want it to increase", last, got)
+					}
+					last = got
+					if total, _ := p["total"].(float64); total != 25 {
+						t.Errorf("total %v, want 25", p["total"])
+					}
+					if msg, _ := p["message"].(string); !strings.Contains(msg, "requests") {
+						t.Errorf("message %q, want it to count requests", msg)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("progress stopped at %v of 25", last)
+				}
+			}
+			if n := len(upstream.take()); n != 3 {
+				t.Errorf("made %d upstream requests, want 3", n)
+			}
+
+			call("get_episodes_batch", map[string]any{"ids": strings.Join(ids, ",")}, nil)
+			upstream.respond(http.StatusOK, `{"episodes":[],"total":0}`)
+			call("get_search", map[string]any{"q": "star wars"}, "search-1")
+			select {
+			case p := <-progress:
+				t.Errorf("got progress %v, want none without a token or for a single request", p)
+			case <-time.After(200 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestPagedProgress(t *testing.T) {
+	for _, tr := range allTransports() {
+		t.Run(tr.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			upstream.handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+				var results []string
+				for i := offset + 1; i <= offset+10; i++ {
+					results = append(results, fmt.Sprintf(`{"id":"r%d"}`, i))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"results":[%s],"count":10,"total":100,"next_offset":%d}`, strings.Join(results, ","), offset+10)
+			}))
+			c := tr.connect(t, testConfig(upstream.URL))
+
+			progress := make(chan map[string]any, 16)
+			c.OnNotification(func(n mcp.JSONRPCNotification) {
+				if n.Method == "notifications/progress" {
+					progress <- n.Params.AdditionalFields
+				}
+			})
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+			req := mcp.CallToolRequest{}
+			req.Params.Name = "get_search"
+			req.Params.Arguments = map[string]any{"q": "star wars", "offset": 20, "pages": 3}
+			req.Params.Meta = &mcp.Meta{ProgressToken: "pages-1"}
+			res, err := c.CallTool(ctx, req)
+			if err != nil {
+				t.Fatalf("CallTool: %v", err)
+			}
+			if res.IsError {
+				t.Fatalf("tool error: %s", resultText(res))
+			}
+
+			var got struct {
+				Results []struct {
+					ID string `json:"id"`
+				} `json:"results"`
+				Count      int `json:"count"`
+				NextOffset int `json:"next_offset"`
+			}
+			if err := json.Unmarshal([]byte(resultText(res)), &got); err != nil {
+				t.Fatalf("result is not JSON: %v", err)
+			}
+			if len(got.Results) != 30 || got.Results[0].ID != "r21" || got.Results[29].ID != "r50" || got.Count != 30 || got.NextOffset != 50 {
+				t.Errorf("got %d results from %v, count %d, next_offset %d; want r21 to r50 going on at 50",
+					len(got.Results), got.Results, got.Count, got.NextOffset)
+			}
+			var offsets []string
+			for _, r := range upstream.take() {
+				offsets = append(offsets, r.Query.Get("offset"))
+			}
+			if !reflect.DeepEqual(offsets, []string{"20", "30", "40"}) {
+				t.Errorf("requested offsets %v, want 20, 30 and 40", offsets)
+			}
+
+			// One update per page; the last one may arrive after the result
+			for page := 1.0; page <= 3; page++ {
+				select {
+				case p := <-progress:
+					if p["progressToken"] != "pages-1" || p["progress"] != page || p["total"] != 3.0 {
+						t.Errorf("progress %v, want page %v of 3 for pages-1", p, page)
+					}
+					want := fmt.Sprintf("Fetched %v of 3 pages, %v items so far", page, page*10)
+					if p["message"] != want {
+						t.Errorf("message %q, want %q", p["message"], want)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("no progress for page %v", page)
+				}
+			}
+		})
+	}
+}
+
+func TestBatchLatestEpisodesAcrossChunks(t *testing.T) {
+	ids := make([]string, 12)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("p%d", i+1)
+	}
+	episodes := func(pubDates ...int) string {
+		var list []string
+		for _, d := range pubDates {
+			list = append(list, fmt.Sprintf(`{"id":"e%d","pub_date_ms":%d}`, d, d))
+		}
+		return "[" + strings.Join(list, ",") + "]"
+	}
+
+	cases := []struct {
+		name          string
+		first, second string // latest_episodes and next_episode_pub_date of each chunk
+		wantLatest    []float64
+		wantNext      any
+	}{
+		{
+			name:       "interleaved",
+			first:      `"latest_episodes":` + episodes(100, 90, 80) + `,"next_episode_pub_date":80`,
+			second:     `"latest_episodes":` + episodes(95, 85, 70) + `,"next_episode_pub_date":70`,
+			wantLatest: []float64{100, 95, 90},
+			wantNext:   float64(90),
+		},
+		{
+			name:       "without latest episodes",
+			first:      `"latest_episodes":[],"next_episode_pub_date":80`,
+			second:     `"latest_episodes":[]`,
+			wantLatest: []float64{},
+			wantNext:   nil,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			upstream := newFakeUpstream(t)
+			upstream.handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				body := tc.second
+				if strings.HasPrefix(r.PostForm.Get("ids"), "p1,") {
+					body = tc.first
+				}
+				w.Header().Set("Content-Type", "application/json")
+				io.WriteString(w, `{"podcasts":[],`+body+`}`)
+			}))
+			c := connectStdio(t, testConfig(upstream.URL))
+
+			res := callTool(t, c, "get_podcasts_batch", map[string]any{"ids": strings.Join(ids, ","), "show_latest_episodes": 1})
+			if res.IsError {
+				t.Fatalf("tool error: %s", resultText(res))
+			}
+			var got struct {
+				Latest []struct {
+					PubDateMs float64 `json:"pub_date_ms"`
+				} `json:"latest_episodes"`
+				Next any `json:"next_episode_pub_date"`
+			}
+			if err := json.Unmarshal([]byte(resultText(res)), &got); err != nil {
+				t.Fatalf("result is not JSON: %v", err)
+			}
+			latest := []float64{}
+			for _, e := range got.Latest {
+				latest = append(latest, e.PubDateMs)
+			}
+			if !reflect.DeepEqual(latest, tc.wantLatest) {
+				t.Errorf("latest_episodes published at %v, want %v", latest, tc.wantLatest)
+			}
+			if got.Next != tc.wantNext {
+				t.Errorf("next_episode_pub_date = %v, want %v", got.Next, tc.wantNext)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ module github.com/listen-api-podcast-search-directory-and-insights-api/mcp-serve
 
 go 1.24.4
 
-require github.com/mark3labs/mcp-go v0.38.0
+require (
+	github.com/mark3labs/mcp-go v0.38.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/bahlo/generic-list-go v0.2.0 // indirect
@@ -13,5 +16,4 @@ require (
 	github.com/spf13/cast v1.7.1 // indirect
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,89 @@
+package listenapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+// An AuthScheme adds one kind of credential from cfg to an outbound request.
+// It returns the header it set, or "" when cfg holds no such credential.
+type AuthScheme func(req *http.Request, cfg *config.APIConfig) (header string)
+
+// DefaultAuthSchemes are the schemes a Client applies unless it is created
+// with its own: the Listen API key plus an optional bearer token or basic
+// credentials, e.g. for a gateway in front of the API.
+func DefaultAuthSchemes() []AuthScheme {
+	return []AuthScheme{APIKeyAuth, BearerAuth, BasicAuth}
+}
+
+// APIKeyAuth sends cfg.APIKey as X-ListenAPI-Key.
+func APIKeyAuth(req *http.Request, cfg *config.APIConfig) string {
+	if cfg.APIKey == "" {
+		return ""
+	}
+	req.Header.Set("X-ListenAPI-Key", cfg.APIKey)
+	return "X-ListenAPI-Key"
+}
+
+// BearerAuth sends cfg.BearerToken as a bearer token in
+// cfg.BearerTokenHeader, Authorization by default.
+func BearerAuth(req *http.Request, cfg *config.APIConfig) string {
+	if cfg.BearerToken == "" {
+		return ""
+	}
+	token := cfg.BearerToken
+	if !strings.HasPrefix(token, "Bearer ") {
+		token = "Bearer " + token
+	}
+	header := headerOrDefault(cfg.BearerTokenHeader, "Authorization")
+	req.Header.Set(header, token)
+	return header
+}
+
+// BasicAuth sends cfg.BasicAuth as basic credentials in cfg.BasicAuthHeader,
+// Authorization by default. The value is either user:password, which is
+// encoded here, or an already encoded token.
+func BasicAuth(req *http.Request, cfg *config.APIConfig) string {
+	if cfg.BasicAuth == "" {
+		return ""
+	}
+	credentials := cfg.BasicAuth
+	switch {
+	case strings.HasPrefix(credentials, "Basic "):
+	case strings.Contains(credentials, ":"):
+		credentials = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	default:
+		credentials = "Basic " + credentials
+	}
+	header := headerOrDefault(cfg.BasicAuthHeader, "Authorization")
+	req.Header.Set(header, credentials)
+	return header
+}
+
+func headerOrDefault(header, def string) string {
+	if header == "" {
+		return def
+	}
+	return http.CanonicalHeaderKey(header)
+}
+
+// authenticate applies every scheme of c to req. Two schemes that would set
+// the same header are a configuration error rather than a silent overwrite.
+func (c *Client) authenticate(req *http.Request, cfg *config.APIConfig) error {
+	set := make(map[string]bool, len(c.auth))
+	for _, scheme := range c.auth {
+		header := scheme(req, cfg)
+		if header == "" {
+			continue
+		}
+		if set[header] {
+			return fmt.Errorf("two configured credentials both use the %s header; set BEARER_TOKEN_HEADER or BASIC_AUTH_HEADER to send one elsewhere", header)
+		}
+		set[header] = true
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package listenapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+)
+
+// MaxBatchSize is the most ids the Listen API accepts in one POST /podcasts
+// or POST /episodes request, counted across all id fields.
+const MaxBatchSize = 10
+
+// batchConcurrency caps how many chunk requests of one batch run at once.
+const batchConcurrency = 4
+
+// podcastBatchFields are the GetPodcastsInBatchForm fields that hold
+// comma-separated lookups and count towards MaxBatchSize.
+var podcastBatchFields = []string{"ids", "rsses", "itunes_ids", "spotify_ids"}
+
+// GetPodcastsBatch looks up any number of podcasts by id, rss url, iTunes id
+// or Spotify id. The lookups are split across as many POST /podcasts requests
+// as MaxBatchSize requires and the responses merged in input order.
+func (c *Client) GetPodcastsBatch(ctx context.Context, form url.Values) (*Response[models.GetPodcastsInBatchResponse], error) {
+	chunks := chunkForm(form, podcastBatchFields)
+	results := make([]*Response[models.GetPodcastsInBatchResponse], len(chunks))
+	err := c.runChunks(ctx, lookupCounts(chunks, podcastBatchFields), func(ctx context.Context, i int) error {
+		result, err := c.GetPodcastsInBatch(ctx, chunks[i])
+		results[i] = result
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 1 {
+		return results[0], nil
+	}
+
+	raws := make([]json.RawMessage, len(results))
+	maxLatest := 0
+	for i, result := range results {
+		raws[i] = result.Raw
+		maxLatest = max(maxLatest, len(result.Value.Latest_episodes))
+	}
+	return mergeChunks[models.GetPodcastsInBatchResponse](raws, func(merged map[string]json.RawMessage, lists map[string][]json.RawMessage) {
+		// Each chunk returns its own latest episodes; keep the overall
+		// latest ones, as many as a single request would have returned.
+		// The next page starts after the oldest of them, whichever chunk it
+		// came from.
+		latest := lists["latest_episodes"]
+		pubDates := make([]int64, len(latest))
+		order := make([]int, len(latest))
+		for i, raw := range latest {
+			var episode struct {
+				PubDateMs int64 `json:"pub_date_ms"`
+			}
+			json.Unmarshal(raw, &episode)
+			pubDates[i], order[i] = episode.PubDateMs, i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return pubDates[order[i]] > pubDates[order[j]]
+		})
+		kept := order[:min(maxLatest, len(order))]
+		sorted := make([]json.RawMessage, 0, len(kept))
+		for _, i := range kept {
+			sorted = append(sorted, latest[i])
+		}
+		lists["latest_episodes"] = sorted
+		if len(kept) > 0 {
+			merged["next_episode_pub_date"] = json.RawMessage(strconv.FormatInt(pubDates[kept[len(kept)-1]], 10))
+		} else {
+			delete(merged, "next_episode_pub_date")
+		}
+	}, "podcasts", "latest_episodes")
+}
+
+// GetEpisodesBatch looks up any number of episodes by id, split across as
+// many POST /episodes requests as MaxBatchSize requires.
+func (c *Client) GetEpisodesBatch(ctx context.Context, form url.Values) (*Response[models.GetEpisodesInBatchResponse], error) {
+	chunks := chunkForm(form, []string{"ids"})
+	results := make([]*Response[models.GetEpisodesInBatchResponse], len(chunks))
+	err := c.runChunks(ctx, lookupCounts(chunks, []string{"ids"}), func(ctx context.Context, i int) error {
+		result, err := c.GetEpisodesInBatch(ctx, chunks[i])
+		results[i] = result
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 1 {
+		return results[0], nil
+	}
+
+	raws := make([]json.RawMessage, len(results))
+	for i, result := range results {
+		raws[i] = result.Raw
+	}
+	return mergeChunks[models.GetEpisodesInBatchResponse](raws, nil, "episodes")
+}
+
+// mergeChunks merges the responses to the chunks of a batch without losing
+// any field: the arrays named by lists are concatenated in chunk order, and
+// every other field is taken from the first chunk. adjust, if not nil, may
+// rework the concatenated arrays and the other fields before they are
+// encoded.
+func mergeChunks[T any](raws []json.RawMessage, adjust func(merged map[string]json.RawMessage, lists map[string][]json.RawMessage), lists ...string) (*Response[T], error) {
+	var merged map[string]json.RawMessage
+	concatenated := make(map[string][]json.RawMessage, len(lists))
+	for i, raw := range raws {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, &DecodeError{Body: raw, Err: err}
+		}
+		if i == 0 {
+			merged = fields
+		}
+		for _, list := range lists {
+			var items []json.RawMessage
+			if err := json.Unmarshal(fields[list], &items); err != nil && fields[list] != nil {
+				return nil, &DecodeError{Body: raw, Err: err}
+			}
+			concatenated[list] = append(concatenated[list], items...)
+		}
+	}
+	if adjust != nil {
+		adjust(merged, concatenated)
+	}
+	for _, list := range lists {
+		items := concatenated[list]
+		if items == nil {
+			items = []json.RawMessage{}
+		}
+		data, err := json.Marshal(items)
+		if err != nil {
+			return nil, err
+		}
+		merged[list] = data
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	return newResponse[T](data), nil
+}
+
+// Progress is how far a batch or a paged call has got: how many of its
+// upstream requests have completed, and how many lookups they answered or,
+// for a paged call, how many items they returned. The total requests of a
+// paged call drop to those made once it reaches the end of the list.
+type Progress struct {
+	Requests, TotalRequests int
+	Lookups, TotalLookups   int
+	Items                   int
+	Paged                   bool
+}
+
+type progressContextKey struct{}
+
+// WithProgress returns a copy of ctx that makes the client call report each
+// time a request of a batch or a paged call made with it completes. Calls
+// never overlap.
+func WithProgress(ctx context.Context, report func(Progress)) context.Context {
+	return context.WithValue(ctx, progressContextKey{}, report)
+}
+
+// runChunks calls fn for chunk indexes 0..len(sizes)-1, at most
+// batchConcurrency at a time, and returns the first error. The remaining
+// chunks are abandoned once one fails. All chunks together share the call
+// timeout. sizes holds the number of lookups in each chunk, for the progress
+// reported to WithProgress.
+func (c *Client) runChunks(ctx context.Context, sizes []int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout())
+	defer cancel()
+
+	report, _ := ctx.Value(progressContextKey{}).(func(Progress))
+	progress := Progress{TotalRequests: len(sizes)}
+	for _, size := range sizes {
+		progress.TotalLookups += size
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		mu       sync.Mutex // guards progress and serializes report
+		firstErr error
+		sem      = make(chan struct{}, batchConcurrency)
+	)
+	for i := range sizes {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			if report != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				progress.Requests++
+				progress.Lookups += sizes[i]
+				report(progress)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// lookupCounts returns the number of comma-separated values of fields in
+// each chunk.
+func lookupCounts(chunks []url.Values, fields []string) []int {
+	counts := make([]int, len(chunks))
+	for i, chunk := range chunks {
+		for _, field := range fields {
+			for _, value := range strings.Split(chunk.Get(field), ",") {
+				if strings.TrimSpace(value) != "" {
+					counts[i]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// chunkForm splits the comma-separated values of fields in form into forms
+// that each carry at most MaxBatchSize values in total. Every other field is
+// copied to each chunk unchanged. A form without any values is returned as
+// the only chunk so that the API can report what is missing.
+func chunkForm(form url.Values, fields []string) []url.Values {
+	type lookup struct{ field, value string }
+	var lookups []lookup
+	for _, field := range fields {
+		for _, value := range strings.Split(form.Get(field), ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				lookups = append(lookups, lookup{field, value})
+			}
+		}
+	}
+	if len(lookups) <= MaxBatchSize {
+		return []url.Values{form}
+	}
+
+	var chunks []url.Values
+	for start := 0; start < len(lookups); start += MaxBatchSize {
+		chunk := url.Values{}
+		for name, values := range form {
+			chunk[name] = values
+		}
+		for _, field := range fields {
+			chunk.Del(field)
+		}
+		values := make(map[string][]string)
+		for _, l := range lookups[start:min(start+MaxBatchSize, len(lookups))] {
+			values[l.field] = append(values[l.field], l.value)
+		}
+		for field, vals := range values {
+			chunk.Set(field, strings.Join(vals, ","))
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
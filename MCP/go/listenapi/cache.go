@@ -0,0 +1,317 @@
+package listenapi
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+// DefaultCacheTTLs are how long successful responses are cached, by endpoint
+// path pattern (see path.Match). Reference data that rarely changes is kept
+// longest; endpoints that are not listed, such as /just_listen, which returns
+// a random episode, are never cached. Only requests that are safe to repeat
+// are cached at all.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"/genres":                     24 * time.Hour,
+	"/languages":                  24 * time.Hour,
+	"/regions":                    24 * time.Hour,
+	"/curated_podcasts":           time.Hour,
+	"/curated_podcasts/*":         6 * time.Hour,
+	"/best_podcasts":              time.Hour,
+	"/podcasts":                   time.Hour, // batch lookup
+	"/podcasts/*":                 time.Hour,
+	"/podcasts/*/recommendations": 6 * time.Hour,
+	"/podcasts/*/audience":        24 * time.Hour,
+	"/podcasts/domains/*":         6 * time.Hour,
+	"/episodes":                   time.Hour, // batch lookup
+	"/episodes/*":                 time.Hour,
+	"/episodes/*/recommendations": 6 * time.Hour,
+	"/playlists":                  5 * time.Minute,
+	"/playlists/*":                5 * time.Minute,
+	"/search":                     15 * time.Minute,
+	"/typeahead":                  15 * time.Minute,
+	"/spellcheck":                 time.Hour,
+	"/related_searches":           time.Hour,
+	"/trending_searches":          30 * time.Minute,
+}
+
+// A CacheStore holds response bodies until they expire.
+type CacheStore interface {
+	Get(key string) (body []byte, ok bool)
+	Set(key string, body []byte, ttl time.Duration)
+}
+
+// responseCache serves repeated requests from a CacheStore and collapses
+// concurrent identical requests into a single upstream call.
+type responseCache struct {
+	store CacheStore
+	ttls  map[string]time.Duration
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is an upstream call that concurrent identical requests wait for.
+type flight struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// newResponseCache returns the cache configured by cfg, or nil when caching
+// is off.
+func newResponseCache(cfg *config.APIConfig) *responseCache {
+	var store CacheStore
+	switch cfg.Cache {
+	case config.CacheOff:
+		return nil
+	case config.CacheDisk:
+		dir := cfg.CacheDir
+		if dir == "" {
+			base, err := os.UserCacheDir()
+			if err != nil {
+				base = os.TempDir()
+			}
+			dir = filepath.Join(base, "listenapi-mcp")
+		}
+		store = NewDiskStore(dir)
+	default:
+		store = NewMemoryStore(cfg.CacheMaxEntries)
+	}
+
+	ttls := make(map[string]time.Duration, len(DefaultCacheTTLs)+len(cfg.CacheTTLs))
+	for pattern, ttl := range DefaultCacheTTLs {
+		ttls[pattern] = ttl
+	}
+	for pattern, ttl := range cfg.CacheTTLs {
+		ttls[pattern] = ttl
+	}
+	return &responseCache{store: store, ttls: ttls, flights: make(map[string]*flight)}
+}
+
+// ttl returns how long a response to a request for method and path may be
+// cached, 0 if not at all. When several patterns match, the most specific,
+// i.e. longest, one wins.
+func (rc *responseCache) ttl(method, p string) time.Duration {
+	if rc == nil || !idempotent(method, p) {
+		return 0
+	}
+	var best string
+	var ttl time.Duration
+	for pattern, d := range rc.ttls {
+		if ok, _ := path.Match(pattern, p); ok && len(pattern) > len(best) {
+			best, ttl = pattern, d
+		}
+	}
+	return ttl
+}
+
+// cacheKey identifies a request by the tenant it is made for and its
+// normalized endpoint and parameters, so that tenants never see each other's
+// responses. It is a hash so that credentials are not kept in the store.
+func cacheKey(cfg *config.APIConfig, method, path string, query, form url.Values) string {
+	h := sha256.New()
+	for _, part := range []string{
+		cfg.BaseURL, cfg.APIKey, cfg.BearerToken, cfg.BasicAuth,
+		method, path, query.Encode(), form.Encode(),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type refreshContextKey struct{}
+
+// WithRefresh returns a copy of ctx that makes the client ignore cached
+// responses to requests made with it. The fresh response replaces the
+// cached one, so that later requests see it too.
+func WithRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshContextKey{}, true)
+}
+
+// get returns the cached body for key, or calls fetch to get it and caches
+// the result for up to ttl. While one fetch for key is running, identical
+// requests wait for it instead of calling the API themselves.
+func (rc *responseCache) get(ctx context.Context, key string, ttl time.Duration, fetch func() ([]byte, http.Header, error)) ([]byte, error) {
+	refresh, _ := ctx.Value(refreshContextKey{}).(bool)
+	for {
+		if body, ok := rc.store.Get(key); ok && !refresh {
+			return body, nil
+		}
+
+		rc.mu.Lock()
+		if f, ok := rc.flights[key]; ok {
+			rc.mu.Unlock()
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return nil, &TransportError{Err: ctx.Err()}
+			}
+			// The caller that made the request gave up; try again ourselves
+			if ctx.Err() == nil && (errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) {
+				continue
+			}
+			return f.body, f.err
+		}
+		f := &flight{done: make(chan struct{})}
+		rc.flights[key] = f
+		rc.mu.Unlock()
+
+		body, header, err := fetch()
+		if err == nil {
+			if ttl := cacheControlTTL(ttl, header); ttl > 0 {
+				rc.store.Set(key, body, ttl)
+			}
+		}
+		f.body, f.err = body, err
+		rc.mu.Lock()
+		delete(rc.flights, key)
+		rc.mu.Unlock()
+		close(f.done)
+		return body, err
+	}
+}
+
+// cacheControlTTL shortens ttl to what the Cache-Control header of a
+// response allows: nothing for no-store and no-cache, at most max-age
+// otherwise.
+func cacheControlTTL(ttl time.Duration, h http.Header) time.Duration {
+	for _, directive := range strings.Split(h.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			if secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				ttl = min(ttl, time.Duration(secs)*time.Second)
+			}
+		}
+	}
+	return ttl
+}
+
+// memoryStore is an in-process CacheStore that evicts the least recently
+// used entry once it is full.
+type memoryStore struct {
+	max int
+
+	mu      sync.Mutex
+	order   *list.List // most recently used first
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	body    []byte
+	expires time.Time
+}
+
+// NewMemoryStore returns an in-process CacheStore holding up to maxEntries
+// responses, or config.DefaultCacheMaxEntries if maxEntries is not positive.
+func NewMemoryStore(maxEntries int) CacheStore {
+	if maxEntries <= 0 {
+		maxEntries = config.DefaultCacheMaxEntries
+	}
+	return &memoryStore{max: maxEntries, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (s *memoryStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryEntry)
+	if time.Now().After(entry.expires) {
+		s.order.Remove(el)
+		delete(s.entries, key)
+		return nil, false
+	}
+	s.order.MoveToFront(el)
+	return entry.body, true
+}
+
+func (s *memoryStore) Set(key string, body []byte, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := &memoryEntry{key: key, body: body, expires: time.Now().Add(ttl)}
+	if el, ok := s.entries[key]; ok {
+		el.Value = entry
+		s.order.MoveToFront(el)
+		return
+	}
+	s.entries[key] = s.order.PushFront(entry)
+	for s.order.Len() > s.max {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// diskStore is a CacheStore that keeps one file per response in a
+// directory, so that the cache survives restarts and can be shared by
+// several server processes. Failing to read or write the directory only
+// makes requests go upstream.
+type diskStore struct {
+	dir string
+}
+
+// NewDiskStore returns a CacheStore that keeps responses in dir, which is
+// created when needed.
+func NewDiskStore(dir string) CacheStore {
+	return &diskStore{dir: dir}
+}
+
+// Each file holds the expiry time in Unix nanoseconds on its first line,
+// followed by the response body.
+func (s *diskStore) Get(key string) ([]byte, bool) {
+	file := filepath.Join(s.dir, key)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+	expiry, body, ok := strings.Cut(string(data), "\n")
+	if !ok {
+		return nil, false
+	}
+	expires, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || time.Now().UnixNano() > expires {
+		os.Remove(file)
+		return nil, false
+	}
+	return []byte(body), true
+}
+
+func (s *diskStore) Set(key string, body []byte, ttl time.Duration) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).UnixNano(), 10)
+	_, err = tmp.WriteString(expires + "\n" + string(body))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	// Rename so that readers never see a partly written file
+	if err != nil || os.Rename(tmp.Name(), filepath.Join(s.dir, key)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,337 @@
+package listenapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+func TestCacheTTL(t *testing.T) {
+	rc := newResponseCache(&config.APIConfig{
+		Cache: config.CacheMemory,
+		CacheTTLs: map[string]time.Duration{
+			"/podcasts/*":   2 * time.Hour, // overrides the default
+			"/podcasts/*/*": time.Minute,   // less specific than the defaults it overlaps
+			"/search":       0,             // turns caching off
+			"/just_listen":  time.Minute,   // turns caching on
+		},
+	})
+	cases := []struct {
+		method, path string
+		want         time.Duration
+	}{
+		{http.MethodGet, "/genres", 24 * time.Hour},
+		{http.MethodGet, "/podcasts/p1", 2 * time.Hour},
+		{http.MethodGet, "/podcasts/p1/recommendations", 6 * time.Hour},
+		{http.MethodGet, "/podcasts/p1/audience", 24 * time.Hour},
+		{http.MethodGet, "/podcasts/domains/example.com", 6 * time.Hour},
+		{http.MethodGet, "/podcasts/p1/other", time.Minute},
+		{http.MethodGet, "/search", 0},
+		{http.MethodGet, "/just_listen", time.Minute},
+		{http.MethodGet, "/unknown", 0},
+		{http.MethodPost, "/podcasts", time.Hour},
+		{http.MethodPost, "/podcasts/submit", 0},
+		{http.MethodDelete, "/podcasts/p1", 0},
+	}
+	for _, tc := range cases {
+		if got := rc.ttl(tc.method, tc.path); got != tc.want {
+			t.Errorf("ttl(%s %s) = %v, want %v", tc.method, tc.path, got, tc.want)
+		}
+	}
+
+	if off := newResponseCache(&config.APIConfig{Cache: config.CacheOff}); off != nil || off.ttl(http.MethodGet, "/genres") != 0 {
+		t.Error("CACHE=off still caches")
+	}
+}
+
+func TestCacheControlTTL(t *testing.T) {
+	cases := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", time.Hour},
+		{"no-store", 0},
+		{"private, No-Cache", 0},
+		{"max-age=60", time.Minute},
+		{"public, max-age=7200", time.Hour},
+		{"max-age=0", 0},
+		{"max-age=soon", time.Hour},
+	}
+	for _, tc := range cases {
+		h := http.Header{}
+		if tc.header != "" {
+			h.Set("Cache-Control", tc.header)
+		}
+		if got := cacheControlTTL(time.Hour, h); got != tc.want {
+			t.Errorf("cacheControlTTL(%q) = %v, want %v", tc.header, got, tc.want)
+		}
+	}
+}
+
+// recordingStore is a memory store that remembers the TTL of every Set.
+type recordingStore struct {
+	CacheStore
+	mu   sync.Mutex
+	ttls map[string]time.Duration
+}
+
+func newRecordingStore() *recordingStore {
+	return &recordingStore{CacheStore: NewMemoryStore(10), ttls: map[string]time.Duration{}}
+}
+
+func (s *recordingStore) Set(key string, body []byte, ttl time.Duration) {
+	s.mu.Lock()
+	s.ttls[key] = ttl
+	s.mu.Unlock()
+	s.CacheStore.Set(key, body, ttl)
+}
+
+func TestResponseCacheGet(t *testing.T) {
+	ctx := context.Background()
+	fetches := 0
+	fetch := func(body string, h http.Header, err error) func() ([]byte, http.Header, error) {
+		return func() ([]byte, http.Header, error) {
+			fetches++
+			return []byte(body), h, err
+		}
+	}
+	store := newRecordingStore()
+	rc := &responseCache{store: store, flights: map[string]*flight{}}
+
+	t.Run("hit", func(t *testing.T) {
+		fetches = 0
+		for range 3 {
+			body, err := rc.get(ctx, "hit", time.Hour, fetch("a", nil, nil))
+			if err != nil || string(body) != "a" {
+				t.Fatalf("get = %q, %v", body, err)
+			}
+		}
+		if fetches != 1 {
+			t.Errorf("%d fetches, want 1", fetches)
+		}
+		if store.ttls["hit"] != time.Hour {
+			t.Errorf("cached for %v, want 1h", store.ttls["hit"])
+		}
+	})
+
+	t.Run("no-store", func(t *testing.T) {
+		fetches = 0
+		h := http.Header{"Cache-Control": {"no-store"}}
+		rc.get(ctx, "no-store", time.Hour, fetch("a", h, nil))
+		rc.get(ctx, "no-store", time.Hour, fetch("a", h, nil))
+		if fetches != 2 {
+			t.Errorf("%d fetches, want 2", fetches)
+		}
+	})
+
+	t.Run("max-age", func(t *testing.T) {
+		rc.get(ctx, "max-age", time.Hour, fetch("a", http.Header{"Cache-Control": {"max-age=30"}}, nil))
+		if store.ttls["max-age"] != 30*time.Second {
+			t.Errorf("cached for %v, want 30s", store.ttls["max-age"])
+		}
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		fetches = 0
+		if _, err := rc.get(ctx, "error", time.Hour, fetch("", nil, &APIError{StatusCode: 500})); err == nil {
+			t.Fatal("error lost")
+		}
+		body, err := rc.get(ctx, "error", time.Hour, fetch("ok", nil, nil))
+		if err != nil || string(body) != "ok" || fetches != 2 {
+			t.Errorf("get = %q, %v after %d fetches, want a fresh ok", body, err, fetches)
+		}
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		fetches = 0
+		rc.get(ctx, "refresh", time.Hour, fetch("old", nil, nil))
+		body, _ := rc.get(WithRefresh(ctx), "refresh", time.Hour, fetch("new", nil, nil))
+		if string(body) != "new" || fetches != 2 {
+			t.Errorf("refreshed get = %q after %d fetches, want new after 2", body, fetches)
+		}
+		if body, _ := rc.get(ctx, "refresh", time.Hour, fetch("newer", nil, nil)); string(body) != "new" {
+			t.Errorf("get after refresh = %q, want the refreshed body cached", body)
+		}
+	})
+}
+
+func TestResponseCacheCollapsesConcurrentRequests(t *testing.T) {
+	rc := &responseCache{store: NewMemoryStore(10), flights: map[string]*flight{}}
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func() ([]byte, http.Header, error) {
+		fetches.Add(1)
+		<-release
+		return []byte("body"), nil, nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	bodies := make([]string, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body, err := rc.get(context.Background(), "key", time.Hour, fetch)
+			if err != nil {
+				t.Errorf("caller %d: %v", i, err)
+			}
+			bodies[i] = string(body)
+		}(i)
+	}
+	// Let every caller reach the flight before it lands
+	for {
+		rc.mu.Lock()
+		f := rc.flights["key"]
+		rc.mu.Unlock()
+		if f != nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("%d fetches, want 1", n)
+	}
+	for i, body := range bodies {
+		if body != "body" {
+			t.Errorf("caller %d got %q", i, body)
+		}
+	}
+}
+
+func TestResponseCacheWaiters(t *testing.T) {
+	rc := &responseCache{store: NewMemoryStore(10), flights: map[string]*flight{}}
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	// The first caller gives up; a waiter fetches for itself instead
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() {
+		_, err := rc.get(leaderCtx, "key", time.Hour, func() ([]byte, http.Header, error) {
+			close(started)
+			<-release
+			return nil, nil, &TransportError{Err: leaderCtx.Err()}
+		})
+		leaderDone <- err
+	}()
+	<-started
+
+	waiterDone := make(chan string)
+	go func() {
+		body, _ := rc.get(context.Background(), "key", time.Hour, func() ([]byte, http.Header, error) {
+			return []byte("mine"), nil, nil
+		})
+		waiterDone <- string(body)
+	}()
+
+	// A waiter that gives up returns at once
+	giveUp, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := rc.get(giveUp, "key", time.Hour, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiter past its deadline: error %v, want the deadline", err)
+	}
+
+	cancelLeader()
+	close(release)
+	if err := <-leaderDone; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader: error %v, want its cancellation", err)
+	}
+	if body := <-waiterDone; body != "mine" {
+		t.Errorf("waiter got %q, want the body of its own fetch", body)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore(2)
+	s.Set("a", []byte("1"), time.Hour)
+	s.Set("b", []byte("2"), time.Hour)
+	s.Get("a") // b is now the least recently used
+	s.Set("c", []byte("3"), time.Hour)
+	if _, ok := s.Get("b"); ok {
+		t.Error("b kept, want the least recently used entry evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := s.Get(key); !ok {
+			t.Errorf("%s evicted", key)
+		}
+	}
+
+	s.Set("short", []byte("x"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := s.Get("short"); ok {
+		t.Error("expired entry returned")
+	}
+}
+
+func TestDiskStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	s := NewDiskStore(dir)
+	if _, ok := s.Get("missing"); ok {
+		t.Error("hit in an empty cache")
+	}
+	s.Set("key", []byte("line 1\nline 2"), time.Hour)
+	if body, ok := s.Get("key"); !ok || string(body) != "line 1\nline 2" {
+		t.Errorf("Get = %q, %v", body, ok)
+	}
+
+	// Another process, or the next run, sees it too
+	if body, ok := NewDiskStore(dir).Get("key"); !ok || string(body) != "line 1\nline 2" {
+		t.Errorf("Get from a second store = %q, %v", body, ok)
+	}
+
+	s.Set("short", []byte("x"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := s.Get("short"); ok {
+		t.Error("expired entry returned")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "short")); !os.IsNotExist(err) {
+		t.Errorf("expired file left behind: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "corrupt"), []byte("not a cache file"), 0o600)
+	if _, ok := s.Get("corrupt"); ok {
+		t.Error("corrupt file returned")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if filepath.Ext(e.Name()) == ".tmp" {
+			t.Errorf("temporary file %s left behind", e.Name())
+		}
+	}
+
+	// A directory that cannot be created only means misses
+	blocked := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(blocked, nil, 0o600)
+	bad := NewDiskStore(filepath.Join(blocked, "cache"))
+	bad.Set("key", []byte("x"), time.Hour)
+	if _, ok := bad.Get("key"); ok {
+		t.Error("hit in an unusable directory")
+	}
+}
+
+func TestCacheKeySeparatesTenants(t *testing.T) {
+	a := &config.APIConfig{BaseURL: "https://api.example", APIKey: "a"}
+	b := &config.APIConfig{BaseURL: "https://api.example", APIKey: "b"}
+	q := url.Values{"q": {"star wars"}}
+	if cacheKey(a, http.MethodGet, "/search", q, nil) == cacheKey(b, http.MethodGet, "/search", q, nil) {
+		t.Error("two API keys share a cache entry")
+	}
+	if cacheKey(a, http.MethodGet, "/search", q, nil) != cacheKey(a, http.MethodGet, "/search", url.Values{"q": {"star wars"}}, nil) {
+		t.Error("identical requests do not share a cache entry")
+	}
+}
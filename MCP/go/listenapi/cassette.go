@@ -0,0 +1,221 @@
+package listenapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+// ErrNotRecorded is returned in replay mode for a request the cassette has
+// no recording of.
+var ErrNotRecorded = errors.New("no recorded response")
+
+// cassette is one file of a cassette directory: every response recorded for
+// one request, in the order they were received.
+type cassette struct {
+	Request   recordedRequest    `json:"request"`
+	Responses []recordedResponse `json:"responses"`
+}
+
+// recordedRequest identifies a request independently of the host it was
+// sent to and of its credentials, so that recordings made against the real
+// API with a real key replay anywhere without one.
+type recordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// cassetteTransport records the requests it sends into a cassette directory
+// or, when replaying, answers them from that directory without touching the
+// network.
+type cassetteTransport struct {
+	dir    string
+	replay bool
+	next   http.RoundTripper // used when recording
+
+	mu     sync.Mutex
+	played map[string]int // responses replayed so far, by cassette file
+}
+
+// newCassetteTransport returns the transport for the cassette mode of cfg,
+// or nil for the default transport when there is none.
+func newCassetteTransport(cfg *config.APIConfig) http.RoundTripper {
+	if cfg.CassetteMode == "" {
+		return nil
+	}
+	dir := cfg.CassetteDir
+	if dir == "" {
+		dir = config.DefaultCassetteDir
+	}
+	return &cassetteTransport{
+		dir:    dir,
+		replay: cfg.CassetteMode == config.CassetteReplay,
+		next:   http.DefaultTransport,
+		played: make(map[string]int),
+	}
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded := recordedRequest{Method: req.Method, Path: req.URL.Path, Query: req.URL.RawQuery}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		recorded.Body = string(body)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	file := filepath.Join(t.dir, cassetteName(recorded))
+
+	if t.replay {
+		return t.play(req, recorded, file)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Credentials never end up in a cassette, even when echoed back
+	var secrets []string
+	for name, vals := range req.Header {
+		if name != "Accept" && name != "Content-Type" && name != "User-Agent" {
+			for _, val := range vals {
+				secrets = append(secrets, credentialForms(val)...)
+			}
+		}
+	}
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	for name, vals := range header {
+		for i, val := range vals {
+			header[name][i] = config.RedactSecrets(val, secrets...)
+		}
+	}
+	if err := t.record(file, recorded, recordedResponse{
+		Status: resp.StatusCode,
+		Header: header,
+		Body:   config.RedactSecrets(string(body), secrets...),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to record response: %w", err)
+	}
+	return resp, nil
+}
+
+// credentialForms returns the forms of the credential in header value val
+// that a response might echo: the value itself, the credential without its
+// scheme, and the decoded user:password of basic credentials. The whole
+// value comes first so that it is redacted as one.
+func credentialForms(val string) []string {
+	forms := []string{val}
+	scheme, credential, ok := strings.Cut(val, " ")
+	if !ok || credential == "" {
+		return forms
+	}
+	forms = append(forms, credential)
+	if strings.EqualFold(scheme, "Basic") {
+		if decoded, err := base64.StdEncoding.DecodeString(credential); err == nil && len(decoded) > 0 {
+			forms = append(forms, string(decoded))
+		}
+	}
+	return forms
+}
+
+// record appends resp to the cassette in file.
+func (t *cassetteTransport) record(file string, req recordedRequest, resp recordedResponse) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c := cassette{Request: req}
+	if data, err := os.ReadFile(file); err == nil {
+		if err := json.Unmarshal(data, &c); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	c.Responses = append(c.Responses, resp)
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o644)
+}
+
+// play answers req with the next response recorded in file. Once all of
+// them have been played, the last one is repeated.
+func (t *cassetteTransport) play(req *http.Request, recorded recordedRequest, file string) (*http.Response, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s %s in %s", ErrNotRecorded, recorded.Method, req.URL.RequestURI(), t.dir)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if len(c.Responses) == 0 {
+		return nil, fmt.Errorf("%w for %s %s in %s", ErrNotRecorded, recorded.Method, req.URL.RequestURI(), t.dir)
+	}
+
+	t.mu.Lock()
+	i := min(t.played[file], len(c.Responses)-1)
+	t.played[file]++
+	t.mu.Unlock()
+
+	resp := c.Responses[i]
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+		StatusCode:    resp.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        resp.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}, nil
+}
+
+// cassetteName is the file holding the recordings of req: a readable prefix
+// from its method and path plus a hash of everything that identifies it.
+func cassetteName(req recordedRequest) string {
+	sum := sha256.Sum256([]byte(req.Method + "\x00" + req.Path + "\x00" + req.Query + "\x00" + req.Body))
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.Trim(req.Path, "/"))
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	return req.Method + "_" + name + "_" + hex.EncodeToString(sum[:4]) + ".json"
+}
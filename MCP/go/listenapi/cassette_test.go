@@ -0,0 +1,143 @@
+package listenapi
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+// echoUpstream answers every request with its credentials echoed back, in
+// the body and in a header, and with a count of the requests so far.
+func echoUpstream(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		r.ParseForm()
+		basic := ""
+		if user, password, ok := r.BasicAuth(); ok {
+			basic = user + ":" + password
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Echo-Key", r.Header.Get("X-ListenAPI-Key"))
+		w.Header().Set("Set-Cookie", "session="+r.Header.Get("X-ListenAPI-Key"))
+		fmt.Fprintf(w, `{"path":%q,"form":%q,"n":%d,"key":%q,"authorization":%q,"token":%q,"basic":%q}`,
+			r.URL.Path, r.PostForm.Encode(), n, r.Header.Get("X-ListenAPI-Key"), r.Header.Get("Authorization"),
+			strings.TrimPrefix(r.Header.Get("X-Gateway-Token"), "Bearer "), basic)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	const (
+		apiKey = "SECRET-API-KEY"
+		token  = "SECRET-BEARER-TOKEN"
+		basic  = "someone:SECRET-PASSWORD"
+	)
+	upstream, requests := echoUpstream(t)
+	dir := t.TempDir()
+
+	// The same session against each client: repeated and POST requests too
+	session := func(c *Client) []string {
+		ctx := context.Background()
+		var raws []string
+		add := func(raw []byte, err error) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			raws = append(raws, string(raw))
+		}
+		genres, err := c.GetGenres(ctx, url.Values{"top_level_only": {"1"}})
+		add(genres.Raw, err)
+		for range 2 {
+			search, err := c.Search(ctx, url.Values{"q": {"star wars"}})
+			add(search.Raw, err)
+		}
+		batch, err := c.GetPodcastsInBatch(ctx, url.Values{"ids": {"a,b"}})
+		add(batch.Raw, err)
+		return raws
+	}
+
+	recorder := NewClient(&config.APIConfig{
+		BaseURL:           upstream.URL,
+		APIKey:            apiKey,
+		BearerToken:       token,
+		BearerTokenHeader: "X-Gateway-Token",
+		BasicAuth:         basic,
+		Cache:             config.CacheOff,
+		CassetteMode:      config.CassetteRecord,
+		CassetteDir:       dir,
+	})
+	recorded := session(recorder)
+	if n := requests.Load(); n != 4 {
+		t.Fatalf("%d requests recorded, want 4", n)
+	}
+	if !strings.Contains(recorded[0], apiKey) {
+		t.Fatal("the upstream does not echo the key; the redaction check below proves nothing")
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		// No credentials and nothing listening: everything comes from the cassettes
+		player := NewClient(&config.APIConfig{
+			BaseURL:      "http://127.0.0.1:1",
+			Cache:        config.CacheOff,
+			CassetteMode: config.CassetteReplay,
+			CassetteDir:  dir,
+		})
+		replayed := session(player)
+		if requests.Load() != 4 {
+			t.Error("replay reached the upstream")
+		}
+		for i := range recorded {
+			want := config.RedactSecrets(recorded[i], apiKey, "Bearer "+token, token, basic,
+				"Basic "+base64.StdEncoding.EncodeToString([]byte(basic)))
+			if replayed[i] != want {
+				t.Errorf("response %d replayed as %s, want %s", i+1, replayed[i], want)
+			}
+		}
+		if recorded[1] == recorded[2] || replayed[1] == replayed[2] {
+			t.Error("repeated request answered with the same response, want each recording in turn")
+		}
+
+		_, err := player.GetGenres(context.Background(), url.Values{"top_level_only": {"0"}})
+		if !errors.Is(err, ErrNotRecorded) {
+			t.Errorf("unrecorded request: error %v, want ErrNotRecorded", err)
+		}
+	})
+
+	t.Run("credentials redacted", func(t *testing.T) {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		if len(files) != 3 {
+			t.Errorf("%d cassettes, want 3", len(files))
+		}
+		encoded := base64.StdEncoding.EncodeToString([]byte(basic))
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, secret := range []string{apiKey, token, basic, "SECRET-PASSWORD", encoded} {
+				if strings.Contains(string(data), secret) {
+					t.Errorf("%s holds %q", filepath.Base(file), secret)
+				}
+			}
+			if strings.Contains(string(data), "Set-Cookie") {
+				t.Errorf("%s holds a cookie", filepath.Base(file))
+			}
+			if !strings.Contains(string(data), "[REDACTED]") {
+				t.Errorf("%s redacts nothing", filepath.Base(file))
+			}
+		}
+	})
+}
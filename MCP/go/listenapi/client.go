@@ -0,0 +1,209 @@
+package listenapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+// Client is the single outbound path from the MCP tools to the Listen API.
+// Request building, authentication, rate limiting, caching, transport and
+// response decoding all live here so that every tool behaves the same way.
+type Client struct {
+	cfg        *config.APIConfig
+	httpClient *http.Client
+	auth       []AuthScheme
+	limits     *limiter
+	cache      *responseCache // nil when caching is off
+	mismatches sync.Map       // Response.Mismatch messages already logged
+}
+
+// NewClient returns a Client that talks to the API described by cfg and
+// authenticates every request with auth, or DefaultAuthSchemes if none are
+// given.
+func NewClient(cfg *config.APIConfig, auth ...AuthScheme) *Client {
+	if len(auth) == 0 {
+		auth = DefaultAuthSchemes()
+	}
+	limits := newLimiter(cfg)
+	if cfg.CassetteMode == config.CassetteReplay {
+		// Replayed requests cost nothing, and replaying the recorded usage
+		// headers must not trip the soft quota
+		limits = newLimiter(&config.APIConfig{})
+	}
+	return &Client{
+		cfg:        cfg,
+		httpClient: &http.Client{Transport: newCassetteTransport(cfg)},
+		auth:       auth,
+		limits:     limits,
+		cache:      newResponseCache(cfg),
+	}
+}
+
+func (c *Client) requestTimeout() time.Duration {
+	if c.cfg.RequestTimeout > 0 {
+		return c.cfg.RequestTimeout
+	}
+	return config.DefaultRequestTimeout
+}
+
+func (c *Client) callTimeout() time.Duration {
+	if c.cfg.CallTimeout > 0 {
+		return c.cfg.CallTimeout
+	}
+	return config.DefaultCallTimeout
+}
+
+type apiKeyContextKey struct{}
+
+// WithAPIKey returns a copy of ctx that makes the client send key as the
+// API key instead of the configured one.
+func WithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+type configContextKey struct{}
+
+// WithConfig returns a copy of ctx that makes the client use cfg for the
+// base URL and credentials of requests made with it, instead of the
+// configuration it was created with. Timeouts and retries always come from
+// the latter.
+func WithConfig(ctx context.Context, cfg *config.APIConfig) context.Context {
+	return context.WithValue(ctx, configContextKey{}, cfg)
+}
+
+// ConfigFrom returns the configuration WithConfig put in ctx, or nil.
+func ConfigFrom(ctx context.Context) *config.APIConfig {
+	cfg, _ := ctx.Value(configContextKey{}).(*config.APIConfig)
+	return cfg
+}
+
+// config returns the configuration for a request made with ctx.
+func (c *Client) config(ctx context.Context) *config.APIConfig {
+	if cfg := ConfigFrom(ctx); cfg != nil {
+		return cfg
+	}
+	return c.cfg
+}
+
+// call sends a request and returns the successful JSON response, decoded
+// into a new T alongside the body as sent; see Response. Idempotent requests
+// that fail with 429, 5xx or a transport error are retried with backoff; see
+// retryDelay. The whole call, retries included, is bounded by the configured
+// call timeout and aborts as soon as ctx is done.
+func call[T any](ctx context.Context, c *Client, method, path string, query, form url.Values) (*Response[T], error) {
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout())
+	defer cancel()
+
+	for attempt := 0; ; attempt++ {
+		data, err := c.do(ctx, method, path, query, form)
+		if err == nil {
+			resp := newResponse[T](data)
+			if resp.Mismatch != nil {
+				c.reportMismatch(method, path, resp.Mismatch)
+			}
+			return resp, nil
+		}
+		delay, ok := c.retryDelay(ctx, method, path, attempt, err)
+		if !ok {
+			return nil, err
+		}
+		if sleep(ctx, delay) != nil {
+			return nil, err
+		}
+	}
+}
+
+// do makes one request, through the cache when the operation is cacheable,
+// and returns the body of a successful response, which must be JSON.
+func (c *Client) do(ctx context.Context, method, path string, query, form url.Values) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout())
+	defer cancel()
+
+	cfg := c.config(ctx)
+	configured := cfg
+	if key, ok := ctx.Value(apiKeyContextKey{}).(string); ok {
+		withKey := *cfg
+		withKey.APIKey = key
+		cfg = &withKey
+	}
+	redact := func(s string) string {
+		return configured.Redact(cfg.Redact(s))
+	}
+	send := func() ([]byte, http.Header, error) {
+		return c.send(ctx, cfg, redact, method, path, query, form)
+	}
+
+	var data []byte
+	var err error
+	if ttl := c.cache.ttl(method, path); ttl > 0 {
+		data, err = c.cache.get(ctx, cacheKey(cfg, method, path, query, form), ttl, send)
+	} else {
+		data, _, err = send()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return nil, &DecodeError{Body: data, Err: errors.New("response is not JSON")}
+	}
+	return data, nil
+}
+
+// send makes one request to the API and returns the body and headers of a
+// successful response.
+func (c *Client) send(ctx context.Context, cfg *config.APIConfig, redact func(string) string, method, path string, query, form url.Values) ([]byte, http.Header, error) {
+	u := cfg.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.Header.Set("Accept", "application/json")
+	if err := c.authenticate(req, cfg); err != nil {
+		return nil, nil, err
+	}
+	if err := c.limits.acquire(ctx, cfg.APIKey); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, &TransportError{Err: &redactedError{msg: redact(err.Error()), cause: err}}
+	}
+	defer resp.Body.Close()
+	c.limits.record(cfg.APIKey, resp.Header)
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Body:       []byte(redact(string(data))),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+	return data, resp.Header, nil
+}
@@ -0,0 +1,61 @@
+package listenapi
+
+import (
+	"fmt"
+	"time"
+)
+
+// APIError is returned when the Listen API answers with a 4xx or 5xx status.
+// RetryAfter is the wait the API asked for with a Retry-After header, if any.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s", e.Body)
+}
+
+// TransportError is returned when the request never produced an HTTP response.
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("request failed: %v", e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned when a successful response is not JSON. Body
+// holds the raw payload.
+type DecodeError struct {
+	Body []byte
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode response: %v", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// redactedError keeps the cause of err available to errors.Is and errors.As
+// while reporting a message with credentials removed.
+type redactedError struct {
+	msg   string
+	cause error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.cause
+}
@@ -0,0 +1,135 @@
+// Code generated by toolgen from openapi.yaml; DO NOT EDIT.
+
+package listenapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+)
+
+// Typed wrappers for every operation in openapi.yaml. Method names follow
+// the spec's operationId; each returns the response both as sent and
+// decoded into its model.
+
+// GetBestPodcasts calls GET /best_podcasts.
+func (c *Client) GetBestPodcasts(ctx context.Context, query url.Values) (*Response[models.BestPodcastsResponse], error) {
+	return call[models.BestPodcastsResponse](ctx, c, http.MethodGet, "/best_podcasts", query, nil)
+}
+
+// GetCuratedPodcasts calls GET /curated_podcasts.
+func (c *Client) GetCuratedPodcasts(ctx context.Context, query url.Values) (*Response[models.GetCuratedPodcastsResponse], error) {
+	return call[models.GetCuratedPodcastsResponse](ctx, c, http.MethodGet, "/curated_podcasts", query, nil)
+}
+
+// GetCuratedPodcastById calls GET /curated_podcasts/{id}.
+func (c *Client) GetCuratedPodcastById(ctx context.Context, id string) (*Response[models.CuratedListFull], error) {
+	return call[models.CuratedListFull](ctx, c, http.MethodGet, "/curated_podcasts/"+url.PathEscape(id), nil, nil)
+}
+
+// GetEpisodesInBatch calls POST /episodes.
+func (c *Client) GetEpisodesInBatch(ctx context.Context, form url.Values) (*Response[models.GetEpisodesInBatchResponse], error) {
+	return call[models.GetEpisodesInBatchResponse](ctx, c, http.MethodPost, "/episodes", nil, form)
+}
+
+// GetEpisodeById calls GET /episodes/{id}.
+func (c *Client) GetEpisodeById(ctx context.Context, id string, query url.Values) (*Response[models.EpisodeFull], error) {
+	return call[models.EpisodeFull](ctx, c, http.MethodGet, "/episodes/"+url.PathEscape(id), query, nil)
+}
+
+// GetEpisodeRecommendations calls GET /episodes/{id}/recommendations.
+func (c *Client) GetEpisodeRecommendations(ctx context.Context, id string, query url.Values) (*Response[models.GetEpisodeRecommendationsResponse], error) {
+	return call[models.GetEpisodeRecommendationsResponse](ctx, c, http.MethodGet, "/episodes/"+url.PathEscape(id)+"/recommendations", query, nil)
+}
+
+// GetGenres calls GET /genres.
+func (c *Client) GetGenres(ctx context.Context, query url.Values) (*Response[models.GetGenresResponse], error) {
+	return call[models.GetGenresResponse](ctx, c, http.MethodGet, "/genres", query, nil)
+}
+
+// JustListen calls GET /just_listen.
+func (c *Client) JustListen(ctx context.Context) (*Response[models.EpisodeSimple], error) {
+	return call[models.EpisodeSimple](ctx, c, http.MethodGet, "/just_listen", nil, nil)
+}
+
+// GetLanguages calls GET /languages.
+func (c *Client) GetLanguages(ctx context.Context) (*Response[models.GetLanguagesResponse], error) {
+	return call[models.GetLanguagesResponse](ctx, c, http.MethodGet, "/languages", nil, nil)
+}
+
+// GetPlaylists calls GET /playlists.
+func (c *Client) GetPlaylists(ctx context.Context, query url.Values) (*Response[models.PlaylistsResponse], error) {
+	return call[models.PlaylistsResponse](ctx, c, http.MethodGet, "/playlists", query, nil)
+}
+
+// GetPlaylistById calls GET /playlists/{id}.
+func (c *Client) GetPlaylistById(ctx context.Context, id string, query url.Values) (*Response[models.PlaylistResponse], error) {
+	return call[models.PlaylistResponse](ctx, c, http.MethodGet, "/playlists/"+url.PathEscape(id), query, nil)
+}
+
+// GetPodcastsInBatch calls POST /podcasts.
+func (c *Client) GetPodcastsInBatch(ctx context.Context, form url.Values) (*Response[models.GetPodcastsInBatchResponse], error) {
+	return call[models.GetPodcastsInBatchResponse](ctx, c, http.MethodPost, "/podcasts", nil, form)
+}
+
+// GetPodcastsByDomainName calls GET /podcasts/domains/{domain_name}.
+func (c *Client) GetPodcastsByDomainName(ctx context.Context, domainName string, query url.Values) (*Response[models.PodcastDomainResponse], error) {
+	return call[models.PodcastDomainResponse](ctx, c, http.MethodGet, "/podcasts/domains/"+url.PathEscape(domainName), query, nil)
+}
+
+// SubmitPodcast calls POST /podcasts/submit.
+func (c *Client) SubmitPodcast(ctx context.Context, form url.Values) (*Response[models.SubmitPodcastResponse], error) {
+	return call[models.SubmitPodcastResponse](ctx, c, http.MethodPost, "/podcasts/submit", nil, form)
+}
+
+// DeletePodcastById calls DELETE /podcasts/{id}.
+func (c *Client) DeletePodcastById(ctx context.Context, id string, query url.Values) (*Response[models.DeletePodcastResponse], error) {
+	return call[models.DeletePodcastResponse](ctx, c, http.MethodDelete, "/podcasts/"+url.PathEscape(id), query, nil)
+}
+
+// GetPodcastById calls GET /podcasts/{id}.
+func (c *Client) GetPodcastById(ctx context.Context, id string, query url.Values) (*Response[models.PodcastFull], error) {
+	return call[models.PodcastFull](ctx, c, http.MethodGet, "/podcasts/"+url.PathEscape(id), query, nil)
+}
+
+// GetPodcastAudience calls GET /podcasts/{id}/audience.
+func (c *Client) GetPodcastAudience(ctx context.Context, id string) (*Response[models.PodcastAudienceResponse], error) {
+	return call[models.PodcastAudienceResponse](ctx, c, http.MethodGet, "/podcasts/"+url.PathEscape(id)+"/audience", nil, nil)
+}
+
+// GetPodcastRecommendations calls GET /podcasts/{id}/recommendations.
+func (c *Client) GetPodcastRecommendations(ctx context.Context, id string, query url.Values) (*Response[models.GetPodcastRecommendationsResponse], error) {
+	return call[models.GetPodcastRecommendationsResponse](ctx, c, http.MethodGet, "/podcasts/"+url.PathEscape(id)+"/recommendations", query, nil)
+}
+
+// GetRegions calls GET /regions.
+func (c *Client) GetRegions(ctx context.Context) (*Response[models.GetRegionsResponse], error) {
+	return call[models.GetRegionsResponse](ctx, c, http.MethodGet, "/regions", nil, nil)
+}
+
+// GetRelatedSearches calls GET /related_searches.
+func (c *Client) GetRelatedSearches(ctx context.Context, query url.Values) (*Response[models.RelatedSearchesResponse], error) {
+	return call[models.RelatedSearchesResponse](ctx, c, http.MethodGet, "/related_searches", query, nil)
+}
+
+// Search calls GET /search.
+func (c *Client) Search(ctx context.Context, query url.Values) (*Response[models.SearchResponse], error) {
+	return call[models.SearchResponse](ctx, c, http.MethodGet, "/search", query, nil)
+}
+
+// Spellcheck calls GET /spellcheck.
+func (c *Client) Spellcheck(ctx context.Context, query url.Values) (*Response[models.SpellCheckResponse], error) {
+	return call[models.SpellCheckResponse](ctx, c, http.MethodGet, "/spellcheck", query, nil)
+}
+
+// GetTrendingSearches calls GET /trending_searches.
+func (c *Client) GetTrendingSearches(ctx context.Context) (*Response[models.TrendingSearchesResponse], error) {
+	return call[models.TrendingSearchesResponse](ctx, c, http.MethodGet, "/trending_searches", nil, nil)
+}
+
+// Typeahead calls GET /typeahead.
+func (c *Client) Typeahead(ctx context.Context, query url.Values) (*Response[models.TypeaheadResponse], error) {
+	return call[models.TypeaheadResponse](ctx, c, http.MethodGet, "/typeahead", query, nil)
+}
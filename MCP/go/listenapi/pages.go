@@ -0,0 +1,169 @@
+package listenapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+)
+
+// MaxPages is the most pages one paged call fetches.
+const MaxPages = 10
+
+// paging describes how to walk the pages of a list endpoint.
+type paging struct {
+	cursor string   // query parameter that selects a page
+	items  string   // field holding the items of a page
+	total  string   // field holding the number of items in the whole list
+	count  string   // field holding the number of items in a page, if any
+	tail   []string // fields taken from the last page, so that paging carries on after it
+
+	// next returns the cursor of the page after the one in fields, and
+	// false after the last page.
+	next func(fields map[string]json.RawMessage) (string, bool)
+}
+
+var searchPaging = paging{
+	cursor: "offset",
+	items:  "results",
+	total:  "total",
+	count:  "count",
+	tail:   []string{"next_offset"},
+	next: func(fields map[string]json.RawMessage) (string, bool) {
+		offset := intField(fields, "next_offset")
+		return strconv.Itoa(offset), offset > 0
+	},
+}
+
+var bestPodcastsPaging = paging{
+	cursor: "page",
+	items:  "podcasts",
+	total:  "total",
+	tail:   []string{"has_next", "next_page_number"},
+	next: func(fields map[string]json.RawMessage) (string, bool) {
+		var hasNext bool
+		json.Unmarshal(fields["has_next"], &hasNext)
+		page := intField(fields, "next_page_number")
+		return strconv.Itoa(page), hasNext && page > 0
+	},
+}
+
+var playlistPaging = paging{
+	cursor: "last_timestamp_ms",
+	items:  "items",
+	total:  "total",
+	tail:   []string{"last_timestamp_ms"},
+	next: func(fields map[string]json.RawMessage) (string, bool) {
+		last := intField(fields, "last_timestamp_ms")
+		return strconv.Itoa(last), last > 0
+	},
+}
+
+// SearchPages fetches up to pages pages of GET /search results, one after
+// the other, and returns them as a single response whose next_offset
+// continues after the last page fetched.
+func (c *Client) SearchPages(ctx context.Context, query url.Values, pages int) (*Response[models.SearchResponse], error) {
+	return walkPages(ctx, c, searchPaging, query, pages, c.Search)
+}
+
+// GetBestPodcastsPages fetches up to pages pages of GET /best_podcasts, one
+// after the other, and returns them as a single response whose
+// next_page_number continues after the last page fetched.
+func (c *Client) GetBestPodcastsPages(ctx context.Context, query url.Values, pages int) (*Response[models.BestPodcastsResponse], error) {
+	return walkPages(ctx, c, bestPodcastsPaging, query, pages, c.GetBestPodcasts)
+}
+
+// GetPlaylistByIdPages fetches up to pages pages of the items of a playlist,
+// one after the other, and returns them as a single response whose
+// last_timestamp_ms continues after the last page fetched.
+func (c *Client) GetPlaylistByIdPages(ctx context.Context, id string, query url.Values, pages int) (*Response[models.PlaylistResponse], error) {
+	return walkPages(ctx, c, playlistPaging, query, pages, func(ctx context.Context, query url.Values) (*Response[models.PlaylistResponse], error) {
+		return c.GetPlaylistById(ctx, id, query)
+	})
+}
+
+// walkPages fetches pages pages with fetch, starting at the page query
+// selects, and merges them with mergeChunks. It stops early after the last
+// page of the list or an empty one. Every page shares the call timeout, and
+// each one fetched is reported to WithProgress.
+func walkPages[T any](ctx context.Context, c *Client, p paging, query url.Values, pages int, fetch func(ctx context.Context, query url.Values) (*Response[T], error)) (*Response[T], error) {
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout())
+	defer cancel()
+	report, _ := ctx.Value(progressContextKey{}).(func(Progress))
+
+	var first *Response[T]
+	var raws []json.RawMessage
+	var last map[string]json.RawMessage
+	items := 0
+	query = cloneValues(query)
+	for len(raws) < pages {
+		result, err := fetch(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = result
+		}
+		raws = append(raws, result.Raw)
+		last = nil
+		if err := json.Unmarshal(result.Raw, &last); err != nil {
+			return nil, &DecodeError{Body: result.Raw, Err: err}
+		}
+		var page []json.RawMessage
+		json.Unmarshal(last[p.items], &page)
+		items += len(page)
+
+		cursor, more := p.next(last)
+		if total := intField(last, p.total); total > 0 && items >= total {
+			more = false
+		}
+		if len(page) == 0 || cursor == query.Get(p.cursor) {
+			more = false
+		}
+		if report != nil {
+			progress := Progress{Requests: len(raws), TotalRequests: pages, Items: items, Paged: true}
+			if !more {
+				progress.TotalRequests = len(raws)
+			}
+			report(progress)
+		}
+		if !more {
+			break
+		}
+		query.Set(p.cursor, cursor)
+	}
+	if len(raws) == 1 {
+		return first, nil
+	}
+
+	return mergeChunks[T](raws, func(merged map[string]json.RawMessage, lists map[string][]json.RawMessage) {
+		for _, field := range p.tail {
+			if value, ok := last[field]; ok {
+				merged[field] = value
+			} else {
+				delete(merged, field)
+			}
+		}
+		if _, ok := merged[p.count]; p.count != "" && ok {
+			merged[p.count] = json.RawMessage(strconv.Itoa(len(lists[p.items])))
+		}
+	}, p.items)
+}
+
+// intField returns the integer in field of fields, or 0.
+func intField(fields map[string]json.RawMessage, field string) int {
+	var n int
+	json.Unmarshal(fields[field], &n)
+	return n
+}
+
+// cloneValues returns a copy of v that can be changed without changing v.
+func cloneValues(v url.Values) url.Values {
+	clone := make(url.Values, len(v))
+	for name, values := range v {
+		clone[name] = append([]string(nil), values...)
+	}
+	return clone
+}
@@ -0,0 +1,228 @@
+package listenapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+// pagedUpstream serves a list of total items, perPage at a time. page
+// tells the page a request asks for, from 1, and respond turns the items of
+// a page and its number into a body, or "" for a 404.
+type pagedUpstream struct {
+	*httptest.Server
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func newPagedUpstream(t *testing.T, total, perPage int, page func(query url.Values) int, respond func(items []string, page int) string) *pagedUpstream {
+	u := &pagedUpstream{}
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.mu.Lock()
+		u.queries = append(u.queries, r.URL.Query())
+		u.mu.Unlock()
+
+		page := max(page(r.URL.Query()), 1)
+		var items []string
+		for i := (page-1)*perPage + 1; i <= min(page*perPage, total); i++ {
+			items = append(items, fmt.Sprintf(`{"id":"i%d"}`, i))
+		}
+		body := respond(items, page)
+		if body == "" {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(u.Close)
+	return u
+}
+
+func (u *pagedUpstream) cursors(name string) []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var cursors []string
+	for _, q := range u.queries {
+		cursors = append(cursors, q.Get(name))
+	}
+	return cursors
+}
+
+// pageParam is the page a request selects with its page parameter.
+func pageParam(query url.Values) int {
+	page, _ := strconv.Atoi(query.Get("page"))
+	return page
+}
+
+// ids returns the ids of the items in list of raw.
+func ids(t *testing.T, raw []byte, list string) []string {
+	t.Helper()
+	var fields map[string]json.RawMessage
+	var items []struct {
+		ID any `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(fields[list], &items); err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, item := range items {
+		got = append(got, fmt.Sprint(item.ID))
+	}
+	return got
+}
+
+func wantIDs(from, to int) []string {
+	var want []string
+	for i := from; i <= to; i++ {
+		want = append(want, fmt.Sprintf("i%d", i))
+	}
+	return want
+}
+
+func TestSearchPages(t *testing.T) {
+	// Pages of 10 through 25 results: offsets 0, 10 and 20
+	byOffset := func(query url.Values) int {
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		return offset/10 + 1
+	}
+	upstream := newPagedUpstream(t, 25, 10, byOffset, func(items []string, page int) string {
+		return fmt.Sprintf(`{"results":[%s],"count":%d,"total":25,"next_offset":%d,"took":0.1}`,
+			strings.Join(items, ","), len(items), page*10)
+	})
+	c := NewClient(&config.APIConfig{BaseURL: upstream.URL, Cache: config.CacheOff})
+
+	var reports []Progress
+	ctx := WithProgress(context.Background(), func(p Progress) { reports = append(reports, p) })
+	query := url.Values{"q": {"star wars"}}
+	resp, err := c.SearchPages(ctx, query, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(t, resp.Raw, "results"); !reflect.DeepEqual(got, wantIDs(1, 25)) {
+		t.Errorf("results %v, want i1 to i25", got)
+	}
+	if got := upstream.cursors("offset"); !reflect.DeepEqual(got, []string{"", "10", "20"}) {
+		t.Errorf("requested offsets %q, want none, 10 and 20, stopping at the total", got)
+	}
+	if resp.Value == nil || resp.Value.Count != 25 || resp.Value.Next_offset != 30 || resp.Value.Total != 25 {
+		t.Errorf("merged response %+v, want a count of 25 and the next offset of the last page", resp.Value)
+	}
+	if query.Get("offset") != "" {
+		t.Error("the caller's query was changed")
+	}
+	want := []Progress{
+		{Requests: 1, TotalRequests: 5, Items: 10, Paged: true},
+		{Requests: 2, TotalRequests: 5, Items: 20, Paged: true},
+		{Requests: 3, TotalRequests: 3, Items: 25, Paged: true},
+	}
+	if !reflect.DeepEqual(reports, want) {
+		t.Errorf("progress %+v, want %+v", reports, want)
+	}
+}
+
+func TestBestPodcastsPages(t *testing.T) {
+	upstream := newPagedUpstream(t, 100, 20, pageParam, func(items []string, page int) string {
+		return fmt.Sprintf(`{"podcasts":[%s],"page_number":%d,"has_next":%t,"next_page_number":%d,"has_previous":%t,"previous_page_number":%d,"total":100,"id":93,"name":"Business"}`,
+			strings.Join(items, ","), page, page < 5, page+1, page > 1, page-1)
+	})
+	c := NewClient(&config.APIConfig{BaseURL: upstream.URL, Cache: config.CacheOff})
+
+	resp, err := c.GetBestPodcastsPages(context.Background(), url.Values{"genre_id": {"93"}, "page": {"2"}}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(t, resp.Raw, "podcasts"); !reflect.DeepEqual(got, wantIDs(21, 60)) {
+		t.Errorf("podcasts %v, want i21 to i60", got)
+	}
+	if got := upstream.cursors("page"); !reflect.DeepEqual(got, []string{"2", "3"}) {
+		t.Errorf("requested pages %q, want 2 and 3", got)
+	}
+	v := resp.Value
+	if v.Page_number != 2 || v.Previous_page_number != 1 || v.Next_page_number != 4 || !v.Has_next || v.Name != "Business" {
+		t.Errorf("merged response %+v, want pages 2 and 3 of Business, going on at page 4", v)
+	}
+
+	// The last page ends the walk
+	resp, err = c.GetBestPodcastsPages(context.Background(), url.Values{"page": {"4"}}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(t, resp.Raw, "podcasts"); !reflect.DeepEqual(got, wantIDs(61, 100)) || resp.Value.Has_next {
+		t.Errorf("podcasts %v, has_next %v, want i61 to i100 and no next page", got, resp.Value.Has_next)
+	}
+}
+
+func TestPlaylistPages(t *testing.T) {
+	// Items go back in time; each page's cursor is its last timestamp
+	var mu sync.Mutex
+	var paths []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path+"?"+r.URL.RawQuery)
+		mu.Unlock()
+		before, _ := strconv.Atoi(r.URL.Query().Get("last_timestamp_ms"))
+		if before == 0 {
+			before = 1000
+		}
+		var items []string
+		last := 0
+		for ts := before - 100; ts > before-300 && ts > 500; ts -= 100 {
+			items = append(items, fmt.Sprintf(`{"id":%d}`, ts))
+			last = ts
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"pl1","items":[%s],"last_timestamp_ms":%d,"total":99}`, strings.Join(items, ","), last)
+	}))
+	t.Cleanup(upstream.Close)
+	c := NewClient(&config.APIConfig{BaseURL: upstream.URL, Cache: config.CacheOff})
+
+	resp, err := c.GetPlaylistByIdPages(context.Background(), "pl1", url.Values{"type": {"episode_list"}}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(t, resp.Raw, "items"); !reflect.DeepEqual(got, []string{"900", "800", "700", "600"}) {
+		t.Errorf("items %v, want 900 to 600", got)
+	}
+	want := []string{
+		"/playlists/pl1?type=episode_list",
+		"/playlists/pl1?last_timestamp_ms=800&type=episode_list",
+		"/playlists/pl1?last_timestamp_ms=600&type=episode_list",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("requests %q, want %q, stopping at the empty page", paths, want)
+	}
+	if resp.Value.Last_timestamp_ms != 0 {
+		t.Errorf("last_timestamp_ms %d, want that of the last page", resp.Value.Last_timestamp_ms)
+	}
+}
+
+func TestPagesFailure(t *testing.T) {
+	upstream := newPagedUpstream(t, 100, 10, pageParam, func(items []string, page int) string {
+		if page == 3 {
+			return ""
+		}
+		return fmt.Sprintf(`{"podcasts":[%s],"has_next":true,"next_page_number":%d}`, strings.Join(items, ","), page+1)
+	})
+	c := NewClient(&config.APIConfig{BaseURL: upstream.URL, Cache: config.CacheOff})
+
+	_, err := c.GetBestPodcastsPages(context.Background(), url.Values{}, 5)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("error %v, want the 404 of page 3", err)
+	}
+}
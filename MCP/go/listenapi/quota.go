@@ -0,0 +1,166 @@
+package listenapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+// Usage is what is known about the monthly quota of one API key: the
+// figures from the usage headers of its latest response, plus the requests
+// sent since.
+type Usage struct {
+	Used            int       // Requests counted in the current billing cycle
+	FreeQuota       int       // Free requests included in the plan, 0 if not reported yet
+	NextBillingDate time.Time // Start of the next billing cycle, zero if not reported yet
+	SoftQuota       int       // Requests after which calls are refused, 0 for none
+}
+
+// QuotaError is returned instead of sending a request once an API key has
+// used up its configured soft quota.
+type QuotaError struct {
+	Usage Usage
+}
+
+func (e *QuotaError) Error() string {
+	msg := fmt.Sprintf("soft quota reached: %d of %d allowed requests used in this billing cycle", e.Usage.Used, e.Usage.SoftQuota)
+	if e.Usage.FreeQuota > 0 {
+		msg += fmt.Sprintf(" (the plan includes %d free requests)", e.Usage.FreeQuota)
+	}
+	msg += "; refusing further Listen API calls"
+	if !e.Usage.NextBillingDate.IsZero() {
+		msg += " until " + e.Usage.NextBillingDate.UTC().Format(time.RFC3339)
+	}
+	return msg + ". Raise SOFT_QUOTA to allow more."
+}
+
+// limiter throttles and meters the requests sent with each API key. A
+// Client is shared by all tools and sessions, so one key has one token
+// bucket and one quota no matter which tool or tenant session uses it.
+type limiter struct {
+	rate             float64 // tokens added per second, 0 for no throttling
+	burst            float64
+	softQuota        int
+	softQuotaPercent float64
+
+	mu   sync.Mutex
+	keys map[string]*keyState
+}
+
+type keyState struct {
+	tokens float64
+	last   time.Time
+	usage  Usage
+}
+
+func newLimiter(cfg *config.APIConfig) *limiter {
+	return &limiter{
+		rate:             cfg.RateLimit,
+		burst:            float64(max(cfg.RateLimitBurst, 1)),
+		softQuota:        cfg.SoftQuota,
+		softQuotaPercent: cfg.SoftQuotaPercent,
+		keys:             make(map[string]*keyState),
+	}
+}
+
+// state returns the state of key. l.mu must be held.
+func (l *limiter) state(key string, now time.Time) *keyState {
+	st, ok := l.keys[key]
+	if !ok {
+		st = &keyState{tokens: l.burst, last: now}
+		l.keys[key] = st
+	}
+	if next := st.usage.NextBillingDate; !next.IsZero() && now.After(next) {
+		// A new billing cycle has started; the next response reports its date
+		st.usage.Used = 0
+		st.usage.NextBillingDate = time.Time{}
+	}
+	st.usage.SoftQuota = l.softQuota
+	if l.softQuotaPercent > 0 {
+		st.usage.SoftQuota = int(float64(st.usage.FreeQuota) * l.softQuotaPercent / 100)
+	}
+	return st
+}
+
+// acquire admits one request sent with key. It refuses the request once the
+// soft quota is used up, and otherwise waits for a token from the key's
+// bucket. A wait that would outlast ctx fails right away.
+func (l *limiter) acquire(ctx context.Context, key string) error {
+	now := time.Now()
+	l.mu.Lock()
+	st := l.state(key, now)
+	if st.usage.SoftQuota > 0 && st.usage.Used >= st.usage.SoftQuota {
+		usage := st.usage
+		l.mu.Unlock()
+		return &QuotaError{Usage: usage}
+	}
+
+	var wait time.Duration
+	if l.rate > 0 {
+		st.tokens = min(l.burst, st.tokens+now.Sub(st.last).Seconds()*l.rate)
+		st.last = now
+		if st.tokens < 1 {
+			wait = time.Duration((1 - st.tokens) / l.rate * float64(time.Second))
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+				l.mu.Unlock()
+				return fmt.Errorf("rate limit of %g requests per second reached; try again in %v", l.rate, wait.Round(time.Millisecond))
+			}
+		}
+		// Take the token now, even if it only becomes available after the
+		// wait, so that concurrent requests queue up behind each other
+		st.tokens--
+	}
+	st.usage.Used++
+	l.mu.Unlock()
+
+	if wait > 0 {
+		if err := sleep(ctx, wait); err != nil {
+			l.mu.Lock()
+			st.tokens++
+			st.usage.Used--
+			l.mu.Unlock()
+			return &TransportError{Err: err}
+		}
+	}
+	return nil
+}
+
+// record updates the usage of key from the usage headers of a response.
+func (l *limiter) record(key string, h http.Header) {
+	used, err := strconv.Atoi(h.Get("X-ListenAPI-Usage"))
+	if err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	st := l.state(key, time.Now())
+	st.usage.Used = used
+	if free, err := strconv.Atoi(h.Get("X-ListenAPI-FreeQuota")); err == nil {
+		st.usage.FreeQuota = free
+	}
+	if next, err := time.Parse(time.RFC3339, h.Get("X-ListenAPI-NextBillingDate")); err == nil {
+		st.usage.NextBillingDate = next
+	}
+}
+
+// usage returns the current usage of key.
+func (l *limiter) usage(key string) Usage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state(key, time.Now()).usage
+}
+
+// Usage returns what is known about the quota of the API key that requests
+// made with ctx are sent with.
+func (c *Client) Usage(ctx context.Context) Usage {
+	key := c.config(ctx).APIKey
+	if k, ok := ctx.Value(apiKeyContextKey{}).(string); ok {
+		key = k
+	}
+	return c.limits.usage(key)
+}
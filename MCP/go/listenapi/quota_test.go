@@ -0,0 +1,174 @@
+package listenapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+// usageHeaders are the usage headers of a response.
+func usageHeaders(used, free int, next time.Time) http.Header {
+	h := http.Header{}
+	h.Set("X-ListenAPI-Usage", strconv.Itoa(used))
+	h.Set("X-ListenAPI-FreeQuota", strconv.Itoa(free))
+	h.Set("X-ListenAPI-NextBillingDate", next.UTC().Format(time.RFC3339))
+	return h
+}
+
+func TestLimiterSoftQuota(t *testing.T) {
+	ctx := context.Background()
+	l := newLimiter(&config.APIConfig{SoftQuota: 3})
+	for i := range 3 {
+		if err := l.acquire(ctx, "key-a"); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	err := l.acquire(ctx, "key-a")
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("request 4: error %v, want a QuotaError", err)
+	}
+	if quotaErr.Usage.Used != 3 || quotaErr.Usage.SoftQuota != 3 {
+		t.Errorf("usage %+v, want 3 of 3 used", quotaErr.Usage)
+	}
+	if !strings.Contains(err.Error(), "Raise SOFT_QUOTA") {
+		t.Errorf("error %q, want it to say how to allow more", err)
+	}
+	if got := l.usage("key-a").Used; got != 3 {
+		t.Errorf("refused request counted: used %d, want 3", got)
+	}
+
+	// Each key has its own quota
+	if err := l.acquire(ctx, "key-b"); err != nil {
+		t.Errorf("other key: %v", err)
+	}
+}
+
+func TestLimiterSoftQuotaPercent(t *testing.T) {
+	ctx := context.Background()
+	next := time.Now().Add(24 * time.Hour)
+	l := newLimiter(&config.APIConfig{SoftQuotaPercent: 50})
+
+	// Unknown until a response reports the free quota
+	if err := l.acquire(ctx, "key"); err != nil {
+		t.Fatalf("before any response: %v", err)
+	}
+	l.record("key", usageHeaders(99, 200, next))
+	if err := l.acquire(ctx, "key"); err != nil {
+		t.Fatalf("at 99 of 100: %v", err)
+	}
+	var quotaErr *QuotaError
+	if err := l.acquire(ctx, "key"); !errors.As(err, &quotaErr) {
+		t.Fatalf("at 100 of 100: error %v, want a QuotaError", err)
+	}
+	if quotaErr.Usage.SoftQuota != 100 || quotaErr.Usage.FreeQuota != 200 || !quotaErr.Usage.NextBillingDate.Equal(next.Truncate(time.Second)) {
+		t.Errorf("usage %+v, want a soft quota of 100 of 200 until %v", quotaErr.Usage, next)
+	}
+}
+
+func TestLimiterBillingCycle(t *testing.T) {
+	ctx := context.Background()
+	l := newLimiter(&config.APIConfig{SoftQuota: 10})
+
+	l.record("key", usageHeaders(10, 10000, time.Now().Add(time.Hour)))
+	if err := l.acquire(ctx, "key"); err == nil {
+		t.Fatal("quota used up, want the request refused")
+	}
+
+	// The cycle ended: usage starts over until the next response says more
+	l.record("key", usageHeaders(10, 10000, time.Now().Add(-time.Second)))
+	usage := l.usage("key")
+	if usage.Used != 0 || !usage.NextBillingDate.IsZero() {
+		t.Errorf("usage %+v after the billing date, want it reset", usage)
+	}
+	if err := l.acquire(ctx, "key"); err != nil {
+		t.Errorf("first request of the new cycle: %v", err)
+	}
+}
+
+func TestLimiterRecord(t *testing.T) {
+	l := newLimiter(&config.APIConfig{})
+	next := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.record("key", usageHeaders(42, 10000, next))
+	if got := l.usage("key"); got.Used != 42 || got.FreeQuota != 10000 || !got.NextBillingDate.Equal(next) {
+		t.Errorf("usage %+v, want 42 of 10000 until %v", got, next)
+	}
+
+	// Responses without usage headers, e.g. errors, change nothing
+	l.record("key", http.Header{})
+	l.record("key", http.Header{"X-Listenapi-Usage": {"many"}})
+	if got := l.usage("key").Used; got != 42 {
+		t.Errorf("used %d, want 42", got)
+	}
+}
+
+func TestLimiterRate(t *testing.T) {
+	ctx := context.Background()
+	l := newLimiter(&config.APIConfig{RateLimit: 20, RateLimitBurst: 2})
+
+	start := time.Now()
+	for i := range 2 {
+		if err := l.acquire(ctx, "key"); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("burst of 2 took %v, want no wait", elapsed)
+	}
+	if err := l.acquire(ctx, "key"); err != nil {
+		t.Fatalf("request 3: %v", err)
+	}
+	// One token every 50ms
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("request 3 after %v, want it to wait for a token", elapsed)
+	}
+
+	// Other keys have their own bucket
+	other := time.Now()
+	if err := l.acquire(ctx, "other"); err != nil || time.Since(other) > 20*time.Millisecond {
+		t.Errorf("other key waited %v with error %v, want no wait", time.Since(other), err)
+	}
+}
+
+func TestLimiterRateDeadline(t *testing.T) {
+	l := newLimiter(&config.APIConfig{RateLimit: 1, RateLimitBurst: 1})
+	if err := l.acquire(context.Background(), "key"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next token is a second away
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := l.acquire(ctx, "key")
+	if err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Errorf("error %v, want the rate limit reported", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("failed after %v, want right away", elapsed)
+	}
+
+	// A wait cut short gives back its token and does not count
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	var transportErr *TransportError
+	if err := l.acquire(ctx, "key"); !errors.As(err, &transportErr) {
+		t.Errorf("cancelled wait: error %v, want a TransportError", err)
+	}
+	l.mu.Lock()
+	st := l.keys["key"]
+	tokens, used := st.tokens, st.usage.Used
+	l.mu.Unlock()
+	if used != 1 {
+		t.Errorf("used %d, want the cancelled request uncounted", used)
+	}
+	if tokens < -0.5 {
+		t.Errorf("%g tokens left, want the token given back", tokens)
+	}
+}
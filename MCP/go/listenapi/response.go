@@ -0,0 +1,48 @@
+package listenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// Response is a successful Listen API response. Raw is the body exactly as
+// the API sent it, so every field survives, in order, with null kept apart
+// from zero and false. Value is the same body decoded into its model for
+// code that needs typed fields; it is a view of Raw, never a substitute for
+// it. Mismatch is set when the body does not fit the model, e.g. a field
+// with another type than the model expects; Value then holds what did fit.
+type Response[T any] struct {
+	Raw      json.RawMessage
+	Value    *T
+	Mismatch error
+}
+
+// MarshalJSON renders the response as the API sent it.
+func (r *Response[T]) MarshalJSON() ([]byte, error) {
+	return r.Raw, nil
+}
+
+// Model returns Value, for code that handles the responses of every
+// operation alike.
+func (r *Response[T]) Model() any {
+	return r.Value
+}
+
+// newResponse wraps data, which must be valid JSON, and decodes its model.
+func newResponse[T any](data []byte) *Response[T] {
+	r := &Response[T]{Raw: data, Value: new(T)}
+	if err := json.Unmarshal(data, r.Value); err != nil {
+		r.Mismatch = fmt.Errorf("response does not match %T: %w", *r.Value, err)
+	}
+	return r
+}
+
+// reportMismatch logs each distinct way a response does not fit its model,
+// once. The caller still gets the full response; the log points at a model
+// in models/models.go that has drifted from the API.
+func (c *Client) reportMismatch(method, path string, err error) {
+	if _, seen := c.mismatches.LoadOrStore(err.Error(), true); !seen {
+		log.Printf("Listen API %s %s: %v", method, path, err)
+	}
+}
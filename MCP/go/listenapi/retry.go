@@ -0,0 +1,107 @@
+package listenapi
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+// readOnlyPosts are POST operations that only look data up, so sending one
+// twice is as harmless as repeating a GET. Every other non-GET request, such
+// as deleting or submitting a podcast, is never retried.
+var readOnlyPosts = map[string]bool{
+	"/podcasts": true, // getPodcastsInBatch
+	"/episodes": true, // getEpisodesInBatch
+}
+
+// idempotent reports whether a failed request may be sent again.
+func idempotent(method, path string) bool {
+	return method == http.MethodGet || method == http.MethodHead ||
+		(method == http.MethodPost && readOnlyPosts[path])
+}
+
+// retryable reports whether err is worth another attempt: rate limiting,
+// server errors, and failures to get any response.
+func retryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	var transportErr *TransportError
+	return errors.As(err, &transportErr) && !errors.Is(err, ErrNotRecorded)
+}
+
+// retryDelay returns how long to wait before retrying a request that failed
+// with err on the given attempt (0 for the first), and false if it should
+// not be retried at all.
+func (c *Client) retryDelay(ctx context.Context, method, path string, attempt int, err error) (time.Duration, bool) {
+	if ctx.Err() != nil || attempt >= c.cfg.MaxRetries || !idempotent(method, path) || !retryable(err) {
+		return 0, false
+	}
+
+	base := c.cfg.RetryBaseDelay
+	if base <= 0 {
+		base = config.DefaultRetryBaseDelay
+	}
+	maxDelay := c.cfg.RetryMaxDelay
+	if maxDelay <= 0 {
+		maxDelay = config.DefaultRetryMaxDelay
+	}
+	// Exponential backoff with equal jitter: half fixed, half random. The
+	// doubling stops at maxDelay, so that no number of attempts overflows.
+	backoff := min(base, maxDelay)
+	for i := 0; i < attempt && backoff < maxDelay; i++ {
+		if backoff > maxDelay/2 {
+			backoff = maxDelay
+		} else {
+			backoff *= 2
+		}
+	}
+	delay := backoff/2 + rand.N(backoff/2+1)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+
+	// Waiting past the deadline only to fail with a timeout would hide err
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return 0, false
+	}
+	return delay, true
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date. It returns 0 when the header is absent or invalid.
+func parseRetryAfter(val string, now time.Time) time.Duration {
+	if val == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(val); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(val); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package listenapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+)
+
+func TestRetryDelay(t *testing.T) {
+	serverErr := &APIError{StatusCode: http.StatusServiceUnavailable}
+	cases := []struct {
+		name          string
+		cfg           config.APIConfig
+		method, path  string
+		attempt       int
+		err           error
+		min, max      time.Duration
+		wantNoRetries bool
+	}{
+		{"first attempt", config.APIConfig{MaxRetries: 3, RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second},
+			http.MethodGet, "/genres", 0, serverErr, 50 * time.Millisecond, 100 * time.Millisecond, false},
+		{"doubles", config.APIConfig{MaxRetries: 5, RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second},
+			http.MethodGet, "/genres", 3, serverErr, 400 * time.Millisecond, 800 * time.Millisecond, false},
+		{"capped", config.APIConfig{MaxRetries: 20, RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second},
+			http.MethodGet, "/genres", 10, serverErr, 500 * time.Millisecond, time.Second, false},
+		{"attempt past the shift width", config.APIConfig{MaxRetries: 1000, RetryBaseDelay: time.Millisecond, RetryMaxDelay: 10 * time.Millisecond},
+			http.MethodGet, "/genres", 100, serverErr, 5 * time.Millisecond, 10 * time.Millisecond, false},
+		{"base above the cap", config.APIConfig{MaxRetries: 3, RetryBaseDelay: time.Second, RetryMaxDelay: 10 * time.Millisecond},
+			http.MethodGet, "/genres", 0, serverErr, 5 * time.Millisecond, 10 * time.Millisecond, false},
+		{"Retry-After wins", config.APIConfig{MaxRetries: 3, RetryBaseDelay: time.Millisecond, RetryMaxDelay: 10 * time.Millisecond},
+			http.MethodGet, "/genres", 0, &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Second}, 2 * time.Second, 2 * time.Second, false},
+		{"transport error", config.APIConfig{MaxRetries: 3, RetryBaseDelay: time.Millisecond, RetryMaxDelay: 10 * time.Millisecond},
+			http.MethodGet, "/genres", 0, &TransportError{Err: errors.New("connection reset")}, 0, 10 * time.Millisecond, false},
+		{"read-only POST", config.APIConfig{MaxRetries: 3, RetryBaseDelay: time.Millisecond, RetryMaxDelay: 10 * time.Millisecond},
+			http.MethodPost, "/episodes", 0, serverErr, 0, 10 * time.Millisecond, false},
+		{"out of retries", config.APIConfig{MaxRetries: 3},
+			http.MethodGet, "/genres", 3, serverErr, 0, 0, true},
+		{"not idempotent", config.APIConfig{MaxRetries: 3},
+			http.MethodPost, "/podcasts/submit", 0, serverErr, 0, 0, true},
+		{"client error", config.APIConfig{MaxRetries: 3},
+			http.MethodGet, "/genres", 0, &APIError{StatusCode: http.StatusNotFound}, 0, 0, true},
+		{"not recorded", config.APIConfig{MaxRetries: 3},
+			http.MethodGet, "/genres", 0, &TransportError{Err: ErrNotRecorded}, 0, 0, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Client{cfg: &tc.cfg}
+			for range 20 {
+				delay, ok := c.retryDelay(context.Background(), tc.method, tc.path, tc.attempt, tc.err)
+				if ok == tc.wantNoRetries {
+					t.Fatalf("retry = %v, want %v", ok, !tc.wantNoRetries)
+				}
+				if delay < tc.min || delay > tc.max {
+					t.Fatalf("delay = %v, want between %v and %v", delay, tc.min, tc.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryDelayRespectsContext(t *testing.T) {
+	c := &Client{cfg: &config.APIConfig{MaxRetries: 3, RetryBaseDelay: time.Second, RetryMaxDelay: time.Second}}
+	serverErr := &APIError{StatusCode: http.StatusBadGateway}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, ok := c.retryDelay(ctx, http.MethodGet, "/genres", 0, serverErr); ok {
+		t.Error("retry past the deadline, want the error returned right away")
+	}
+	cancel()
+	if _, ok := c.retryDelay(ctx, http.MethodGet, "/genres", 0, serverErr); ok {
+		t.Error("retry after cancellation, want none")
+	}
+}
+
+func TestIdempotent(t *testing.T) {
+	cases := []struct {
+		method, path string
+		want         bool
+	}{
+		{http.MethodGet, "/search", true},
+		{http.MethodHead, "/genres", true},
+		{http.MethodPost, "/podcasts", true},
+		{http.MethodPost, "/episodes", true},
+		{http.MethodPost, "/podcasts/submit", false},
+		{http.MethodPost, "/playlists", false},
+		{http.MethodDelete, "/podcasts/p1", false},
+		{http.MethodPut, "/podcasts", false},
+	}
+	for _, tc := range cases {
+		if got := idempotent(tc.method, tc.path); got != tc.want {
+			t.Errorf("idempotent(%s %s) = %v, want %v", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		val  string
+		want time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{"soon", 0},
+		{"1.5", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{now.Format(http.TimeFormat), 0},
+	}
+	for _, tc := range cases {
+		if got := parseRetryAfter(tc.val, now); got != tc.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tc.val, got, tc.want)
+		}
+	}
+}
@@ -12,9 +12,15 @@ import (
 
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "mock" {
+		runMock(os.Args[2:])
+		return
+	}
+
 	cfg, err := config.LoadAPIConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
@@ -28,58 +34,32 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	// HTTP/HTTPS Mode - if transport is "http", "HTTP", "https", or "HTTPS"
-	if transport == "http" || transport == "HTTP" || transport == "https" || transport == "HTTPS" {
+	// HTTP/HTTPS/SSE Mode - if transport is "http", "HTTP", "https", "HTTPS", "sse" or "SSE"
+	if transport == "http" || transport == "HTTP" || transport == "https" || transport == "HTTPS" || transport == "sse" || transport == "SSE" {
 		port := cfg.Port
 		if port == "" {
 			log.Fatalf("PORT environment variable is required for HTTP/HTTPS mode. Please set PORT environment variable.")
 		}
 
-		// Determine if HTTPS mode and normalize transport
+		// Determine if HTTPS or SSE mode and normalize transport
 		isHTTPS := transport == "https" || transport == "HTTPS"
-		if isHTTPS {
+		isSSE := transport == "sse" || transport == "SSE"
+		switch {
+		case isHTTPS:
 			transport = "HTTPS"
-		} else {
+		case isSSE:
+			transport = "SSE"
+		default:
 			transport = "HTTP"
 		}
 		
 		log.Printf("Running in %s mode on port %s", transport, port)
 
-		mux := http.NewServeMux()
-		mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
-			// Read headers for dynamic config
-			apiCfg := &config.APIConfig{
-				BaseURL:     r.Header.Get("API_BASE_URL"),
-				BearerToken: r.Header.Get("BEARER_TOKEN"),
-				APIKey:      r.Header.Get("API_KEY"),
-				BasicAuth:   r.Header.Get("BASIC_AUTH"),
-			}
-
-			if apiCfg.BaseURL == "" {
-				http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
-				return
-			}
-
-			log.Printf("Incoming HTTP request - BaseURL: %s", apiCfg.BaseURL)
-
-			// Create MCP server for this request
-			mcpSrv := createMCPServer(apiCfg, transport)
-			handler := server.NewStreamableHTTPServer(mcpSrv, server.WithHTTPContextFunc(
-				func(ctx context.Context, req *http.Request) context.Context {
-					return context.WithValue(ctx, "apiConfig", apiCfg)
-				},
-			))
-
-			handler.ServeHTTP(w, r)
-		})
-
-		mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			w.Write([]byte(`{"status":"ok"}`))
-		})
-
 		addr := net.JoinHostPort("0.0.0.0", port)
-		httpServer := &http.Server{Addr: addr, Handler: mux}
+		httpServer := &http.Server{Addr: addr}
+		handler, sse, stopSessions := newHTTPHandler(cfg, transport, httpServer)
+		defer stopSessions()
+		httpServer.Handler = handler
 
 		go func() {
 			// Check if HTTPS mode
@@ -108,7 +88,12 @@ func main() {
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		if err := httpServer.Shutdown(ctx); err != nil {
+		shutdown := httpServer.Shutdown
+		if sse != nil {
+			// Closes the open SSE streams, then shuts httpServer down
+			shutdown = sse.Shutdown
+		}
+		if err := shutdown(ctx); err != nil {
 			log.Printf("Shutdown error: %v", err)
 		} else {
 			log.Println("HTTP server shutdown complete")
@@ -118,9 +103,9 @@ func main() {
 
 	// STDIO Mode - default when no transport or transport is "stdio"
 	log.Println("Running in STDIO mode")
-	mcp := createMCPServer(cfg, "STDIO")
+	mcp := createMCPServer(cfg, "STDIO", &server.Hooks{})
 	go func() {
-		if err := server.ServeStdio(mcp); err != nil {
+		if err := serveStdio(context.Background(), mcp, os.Stdin, os.Stdout); err != nil {
 			log.Fatalf("STDIO error: %v", err)
 		}
 	}()
@@ -128,18 +113,147 @@ func main() {
 	log.Println("Received shutdown signal. Exiting STDIO mode.")
 }
 
-func createMCPServer(cfg *config.APIConfig, mode string) *server.MCPServer {
-	mcp := server.NewMCPServer("Listen API: Podcast Search, Directory, and Insights API", "2.0",
+// mcpServer is an MCP server of mcp-go together with what this server
+// handles itself: the methods mcp-go does not implement, and the state a
+// session leaves behind when it ends.
+type mcpServer struct {
+	*server.MCPServer
+	extra   *extraMethods
+	watcher *podcastWatcher
+}
+
+// endSession forgets everything about a session that has ended.
+func (srv *mcpServer) endSession(sessionID string) {
+	srv.watcher.endSession(sessionID)
+}
+
+func createMCPServer(cfg *config.APIConfig, mode string, hooks *server.Hooks) *mcpServer {
+	calls := newInflightCalls()
+	opts := []server.ServerOption{
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(true, false),
+		server.WithPromptCapabilities(false),
 		server.WithRecovery(),
-	)
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(progressMiddleware),
+	}
+	opts = append(opts, calls.register(hooks)...)
+	mcp := server.NewMCPServer("Listen API: Podcast Search, Directory, and Insights API", "2.0", opts...)
+	mcp.AddNotificationHandler("notifications/cancelled", calls.handleCancelled)
 
-	tools := GetAll(cfg)
+	// Tools and resources share one client, and so its cache and rate limit
+	client := listenapi.NewClient(cfg)
+	tools := toolsFor(cfg, client)
 	log.Printf("Loaded %d tools for %s mode", len(tools), mode)
 
 	for _, tool := range tools {
 		mcp.AddTool(tool.Definition, tool.Handler)
 	}
 
-	return mcp
-}
\ No newline at end of file
+	resources := addResources(mcp, client)
+	log.Printf("Loaded %d resources for %s mode", resources, mode)
+
+	prompts := addPrompts(mcp)
+	log.Printf("Loaded %d prompts for %s mode", prompts, mode)
+
+	srv := &mcpServer{
+		MCPServer: mcp,
+		extra:     newExtraMethods(calls),
+		watcher:   newPodcastWatcher(mcp, client, cfg),
+	}
+	srv.watcher.register(srv.extra)
+	newCompletions(client, cfg).register(srv.extra)
+	return srv
+}
+
+// newHTTPHandler builds what HTTP, HTTPS and SSE mode serve for transport:
+// one MCP server for every session, the /mcp endpoint, the health check and,
+// in SSE mode, the legacy SSE endpoints of sse, which needs httpServer to
+// shut down. stop ends the expiry of idle sessions.
+func newHTTPHandler(cfg *config.APIConfig, transport string, httpServer *http.Server) (handler http.Handler, sse *server.SSEServer, stop func()) {
+	isSSE := transport == "SSE"
+
+	// One MCP server serves every session; each session carries the API
+	// configuration its client sent when it initialized.
+	hooks := &server.Hooks{}
+	sseSessions := newSessionStore(cfg)
+	if isSSE {
+		sseSessions.registerSSE(hooks)
+	}
+	mcpSrv := createMCPServer(cfg, transport, hooks)
+	sessions := newSessionStore(cfg)
+	sessions.onEnd = mcpSrv.endSession
+	sseSessions.onEnd = mcpSrv.endSession
+	sessionCtx, stop := context.WithCancel(context.Background())
+	go sessions.expireIdle(sessionCtx, cfg.SessionCleanupInterval)
+
+	streamable := server.NewStreamableHTTPServer(mcpSrv.MCPServer,
+		server.WithSessionIdManager(sessions),
+		server.WithHTTPContextFunc(sessions.contextFunc),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", sessions.tenantGuard(func(r *http.Request) string {
+		return r.Header.Get(server.HeaderKeySessionID)
+	}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		if sessionID == "" {
+			if err := sessions.checkBaseURL(r.Header); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+		switch {
+		case sessionID == "" && r.Method == http.MethodPost:
+			// A new session: its configuration comes from this request
+			if r.Header.Get("API_BASE_URL") == "" && cfg.BaseURL == "" {
+				http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
+				return
+			}
+			w = completionsWriter{w}
+		case sessionID != "" && r.Method == http.MethodGet:
+			if !sessions.has(sessionID) {
+				http.Error(w, "Session not found", http.StatusNotFound)
+				return
+			}
+			defer sessions.listen(sessionID)()
+		case sessionID != "" && r.Method == http.MethodPost:
+			// Methods mcp-go does not implement are answered here
+			if terminated, err := sessions.Validate(sessionID); err == nil && !terminated {
+				if sessionCfg := sessions.config(sessionID); sessionCfg != nil &&
+					mcpSrv.handleStreamable(listenapi.WithConfig(r.Context(), sessionCfg), w, r, sessionID) {
+					return
+				}
+			}
+			w, r = trackNotifications(w, r)
+		}
+		streamable.ServeHTTP(w, r)
+	})))
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok"}`))
+	})
+
+	// SSE mode also serves the legacy SSE transport for older clients
+	if isSSE {
+		sse = server.NewSSEServer(mcpSrv.MCPServer,
+			server.WithHTTPServer(httpServer),
+			server.WithSSEContextFunc(sseSessions.contextFunc),
+			server.WithKeepAlive(true),
+		)
+		mux.Handle("/sse", sseSessions.sseHandler(declaringCompletions(sse.SSEHandler())))
+		messages := sse.MessageHandler()
+		mux.Handle("/message", sseSessions.tenantGuard(func(r *http.Request) string {
+			return r.URL.Query().Get("sessionId")
+		}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sessionID := r.URL.Query().Get("sessionId")
+			if sessionCfg := sseSessions.config(sessionID); sessionCfg != nil && r.Method == http.MethodPost &&
+				mcpSrv.handleSSEMessage(listenapi.WithConfig(r.Context(), sessionCfg), sse, w, r, sessionID) {
+				return
+			}
+			messages.ServeHTTP(w, r)
+		})))
+	}
+	return mux, sse, stop
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// stdioSessionID is the id mcp-go gives the one session of the stdio
+// transport.
+const stdioSessionID = "stdio"
+
+// methodHandler answers a request for an extra method. sessionID is the
+// session the request arrived on; ctx carries its API configuration.
+type methodHandler func(ctx context.Context, sessionID string, params json.RawMessage) (any, error)
+
+// extraMethods answers the JSON-RPC methods of the MCP spec that mcp-go does
+// not implement, such as resources/subscribe. Every transport hands it each
+// incoming message before the MCP server sees it; see serveStdio,
+// handleStreamable and handleSSEMessage.
+type extraMethods struct {
+	handlers map[mcp.MCPMethod]methodHandler
+	calls    *inflightCalls // makes requests cancellable by notifications/cancelled
+}
+
+func newExtraMethods(calls *inflightCalls) *extraMethods {
+	return &extraMethods{handlers: make(map[mcp.MCPMethod]methodHandler), calls: calls}
+}
+
+// add makes m answer requests for method with h.
+func (m *extraMethods) add(method mcp.MCPMethod, h methodHandler) {
+	m.handlers[method] = h
+}
+
+// rpcError is an error answered with its own JSON-RPC error code instead of
+// an internal error.
+type rpcError struct {
+	code    int
+	message string
+}
+
+func (e *rpcError) Error() string { return e.message }
+
+// invalidParams reports a request whose params the method cannot accept.
+func invalidParams(format string, args ...any) error {
+	return &rpcError{code: mcp.INVALID_PARAMS, message: fmt.Sprintf(format, args...)}
+}
+
+// extraRequest is a request for one of the extra methods.
+type extraRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *mcp.RequestId  `json:"id"`
+	Method  mcp.MCPMethod   `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// parse returns message as a request if it is one for m's methods. ok is
+// false for every other message, which is for the MCP server.
+func (m *extraMethods) parse(message []byte) (req *extraRequest, ok bool) {
+	if err := json.Unmarshal(message, &req); err != nil || req == nil || req.ID == nil || req.JSONRPC != mcp.JSONRPC_VERSION {
+		return nil, false
+	}
+	if _, ok := m.handlers[req.Method]; !ok {
+		return nil, false
+	}
+	return req, true
+}
+
+// handle answers message if it is a request for one of m's methods. ok is
+// false for every other message, which is for the MCP server.
+func (m *extraMethods) handle(ctx context.Context, sessionID string, message []byte) (response mcp.JSONRPCMessage, ok bool) {
+	req, ok := m.parse(message)
+	if !ok {
+		return nil, false
+	}
+	return m.answer(ctx, sessionID, req), true
+}
+
+// answer runs the handler of req. Until it returns, notifications/cancelled
+// for req cancels ctx.
+func (m *extraMethods) answer(ctx context.Context, sessionID string, req *extraRequest) mcp.JSONRPCMessage {
+	ctx, done := m.calls.track(ctx, sessionID, req.ID.Value())
+	defer done()
+	result, err := m.handlers[req.Method](ctx, sessionID, req.Params)
+	if err != nil {
+		code := mcp.INTERNAL_ERROR
+		var rpcErr *rpcError
+		if errors.As(err, &rpcErr) {
+			code = rpcErr.code
+		}
+		return mcp.NewJSONRPCError(*req.ID, code, err.Error(), nil)
+	}
+	return mcp.JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: *req.ID, Result: result}
+}
+
+// serveStdio serves srv over stdin and stdout until ctx is done or stdin
+// ends. Requests for extra methods are answered here, each in its own
+// goroutine so that a slow one holds up no other message; every other line
+// is passed on to the stdio transport of mcp-go.
+func serveStdio(ctx context.Context, srv *mcpServer, stdin io.Reader, stdout io.Writer) error {
+	out := &lineWriter{w: stdout}
+	forward, toServer := io.Pipe()
+	handlerCtx, stopHandlers := context.WithCancel(ctx)
+	var (
+		mu       sync.Mutex
+		stopped  bool
+		handlers sync.WaitGroup
+	)
+	go func() {
+		reader := bufio.NewReader(stdin)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				if req, ok := srv.extra.parse(line); ok {
+					mu.Lock()
+					if stopped {
+						mu.Unlock()
+						return
+					}
+					handlers.Add(1)
+					mu.Unlock()
+					go func() {
+						defer handlers.Done()
+						out.writeMessage(srv.extra.answer(handlerCtx, stdioSessionID, req))
+					}()
+				} else if _, werr := toServer.Write(line); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				toServer.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	err := server.NewStdioServer(srv.MCPServer).Listen(ctx, forward, out)
+	forward.Close()
+	// No request may outlive the session, e.g. to subscribe after it ended
+	mu.Lock()
+	stopped = true
+	mu.Unlock()
+	stopHandlers()
+	handlers.Wait()
+	srv.endSession(stdioSessionID)
+	return err
+}
+
+// lineWriter serializes writes to stdout, so that the messages of mcp-go,
+// which writes each with a single Write, and those of serveStdio never
+// interleave.
+type lineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	if _, err := lw.w.Write(declareCompletions(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (lw *lineWriter) writeMessage(message mcp.JSONRPCMessage) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	lw.Write(append(data, '\n'))
+}
+
+// readBody returns the body of r and leaves it in place for the next
+// handler.
+func readBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err
+}
+
+// handleStreamable answers a POST to /mcp of session sessionID if it is a
+// request for an extra method. The response is the JSON body of the reply.
+func (srv *mcpServer) handleStreamable(ctx context.Context, w http.ResponseWriter, r *http.Request, sessionID string) bool {
+	body, err := readBody(r)
+	if err != nil {
+		return false
+	}
+	response, ok := srv.extra.handle(ctx, sessionID, body)
+	if !ok {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(server.HeaderKeySessionID, sessionID)
+	json.NewEncoder(w).Encode(response)
+	return true
+}
+
+// handleSSEMessage answers a POST to /message of legacy SSE session
+// sessionID if it is a request for an extra method. Like every reply on
+// that transport, the response travels on the session's event stream.
+func (srv *mcpServer) handleSSEMessage(ctx context.Context, sse *server.SSEServer, w http.ResponseWriter, r *http.Request, sessionID string) bool {
+	body, err := readBody(r)
+	if err != nil {
+		return false
+	}
+	response, ok := srv.extra.handle(ctx, sessionID, body)
+	if !ok {
+		return false
+	}
+	if err := sse.SendEventToSession(sessionID, response); err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return true
+	}
+	w.WriteHeader(http.StatusAccepted)
+	return true
+}
+
+// declareCompletions adds the completions capability to the initialize
+// result in p, a message as mcp-go writes it, since mcp-go has no field for
+// it. p may be framed, e.g. as a server-sent event. Every other message is
+// returned as it is.
+func declareCompletions(p []byte) []byte {
+	if !bytes.Contains(p, []byte(`"serverInfo"`)) {
+		return p
+	}
+	start, end := bytes.IndexByte(p, '{'), bytes.LastIndexByte(p, '}')
+	if start < 0 || end < start {
+		return p
+	}
+	var message map[string]json.RawMessage
+	var result map[string]json.RawMessage
+	var capabilities map[string]json.RawMessage
+	if json.Unmarshal(p[start:end+1], &message) != nil ||
+		json.Unmarshal(message["result"], &result) != nil || result["serverInfo"] == nil ||
+		json.Unmarshal(result["capabilities"], &capabilities) != nil || capabilities == nil {
+		return p
+	}
+	capabilities["completions"] = json.RawMessage(`{}`)
+	var err error
+	if result["capabilities"], err = json.Marshal(capabilities); err != nil {
+		return p
+	}
+	if message["result"], err = json.Marshal(result); err != nil {
+		return p
+	}
+	patched, err := json.Marshal(message)
+	if err != nil {
+		return p
+	}
+	return slices.Concat(p[:start], patched, p[end+1:])
+}
+
+// completionsWriter passes what the HTTP transports of mcp-go write through
+// declareCompletions.
+type completionsWriter struct {
+	http.ResponseWriter
+}
+
+func (w completionsWriter) Write(p []byte) (int, error) {
+	if _, err := w.ResponseWriter.Write(declareCompletions(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w completionsWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w completionsWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// declaringCompletions serves next with a completionsWriter.
+func declaringCompletions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(completionsWriter{w}, r)
+	})
+}
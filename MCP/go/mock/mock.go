@@ -0,0 +1,499 @@
+// Package mock serves a fake Listen API generated from openapi.yaml, so that
+// the MCP server and its tools can run without network access or an API key.
+// Every path and method in the spec is served with either the examples from
+// the spec or fake data generated from the response schema.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Options configure a mock server.
+type Options struct {
+	// Seed makes generated data and injected errors reproducible. The same
+	// seed and request always get the same response.
+	Seed uint64
+	// Fake generates varied data from the response schemas instead of
+	// repeating the examples in the spec.
+	Fake bool
+	// ErrorRate is the fraction of requests, from 0 to 1, that fail with one
+	// of ErrorStatuses instead of being answered.
+	ErrorRate float64
+	// ErrorStatuses are the statuses injected errors use, 500 if empty.
+	ErrorStatuses []int
+	// Latency delays every response.
+	Latency time.Duration
+}
+
+// Server is an http.Handler that answers every operation in a spec.
+type Server struct {
+	opts   Options
+	doc    map[string]any
+	routes []route
+
+	mu     sync.Mutex
+	errors *rand.Rand // decides which requests fail, in request order
+	usage  int
+}
+
+// NewServer returns a mock of the API described by the OpenAPI document spec.
+func NewServer(spec []byte, opts Options) (*Server, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	if len(opts.ErrorStatuses) == 0 {
+		opts.ErrorStatuses = []int{http.StatusInternalServerError}
+	}
+	s := &Server{
+		opts:   opts,
+		doc:    doc,
+		errors: rand.New(rand.NewPCG(opts.Seed, 0)),
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("spec has no paths")
+	}
+	for path, item := range paths {
+		methods, _ := item.(map[string]any)
+		for method, op := range methods {
+			op, ok := op.(map[string]any)
+			if !ok {
+				continue
+			}
+			schema := s.responseSchema(op)
+			if schema == nil {
+				continue
+			}
+			s.routes = append(s.routes, route{
+				method:   strings.ToUpper(method),
+				segments: strings.Split(strings.Trim(path, "/"), "/"),
+				handler:  s.operation(schema),
+			})
+		}
+	}
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Latency > 0 {
+		select {
+		case <-time.After(s.opts.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.usage++
+	usage := s.usage
+	var status int
+	if s.opts.ErrorRate > 0 && s.errors.Float64() < s.opts.ErrorRate {
+		status = s.opts.ErrorStatuses[s.errors.IntN(len(s.opts.ErrorStatuses))]
+	}
+	s.mu.Unlock()
+
+	now := time.Now().UTC()
+	w.Header().Set("X-ListenAPI-FreeQuota", "25000")
+	w.Header().Set("X-ListenAPI-Usage", strconv.Itoa(usage))
+	w.Header().Set("X-ListenAPI-NextBillingDate", time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339))
+
+	if status != 0 {
+		log.Printf("mock: %s %s -> injected %d", r.Method, r.URL.RequestURI(), status)
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		writeJSON(w, status, map[string]any{"status": status, "message": http.StatusText(status) + " (injected by mock)"})
+		return
+	}
+	handler := s.match(r)
+	if handler == nil {
+		writeJSON(w, http.StatusNotFound, map[string]any{"status": http.StatusNotFound, "message": "No such operation in openapi.yaml"})
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// route is one path and method of the spec. Segments such as {id} match
+// any value.
+type route struct {
+	method   string
+	segments []string
+	handler  http.Handler
+}
+
+// match returns the handler for r and sets its path values. When several
+// routes match, such as /podcasts/{id}/audience and
+// /podcasts/domains/{domain_name}, the one with more literal segments wins.
+// Segments are split before unescaping, so an id may contain "/".
+func (s *Server) match(r *http.Request) http.Handler {
+	segments := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	for i, seg := range segments {
+		if unescaped, err := url.PathUnescape(seg); err == nil {
+			segments[i] = unescaped
+		}
+	}
+	var best *route
+	bestLiterals := -1
+	for i := range s.routes {
+		rt := &s.routes[i]
+		if rt.method != r.Method || len(rt.segments) != len(segments) {
+			continue
+		}
+		literals := 0
+		for j, seg := range rt.segments {
+			if !isWildcard(seg) {
+				if seg != segments[j] {
+					literals = -1
+					break
+				}
+				literals++
+			}
+		}
+		if literals > bestLiterals {
+			best, bestLiterals = rt, literals
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	for j, seg := range best.segments {
+		if isWildcard(seg) {
+			r.SetPathValue(strings.Trim(seg, "{}"), segments[j])
+		}
+	}
+	return best.handler
+}
+
+func isWildcard(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// operation answers requests for one operation with data generated from the
+// schema of its 200 response.
+func (s *Server) operation(schema map[string]any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+
+		// Seed from the request, so that it always gets the same answer
+		h := fnv.New64a()
+		fmt.Fprintf(h, "%s %s?%s\n%s", r.Method, r.URL.Path, r.URL.RawQuery, body)
+		g := &generator{
+			s:   s,
+			rng: rand.New(rand.NewPCG(s.opts.Seed, h.Sum64())),
+			ids: splitIDs(form.Get("ids")),
+		}
+		out := g.value(schema, "", 0)
+
+		// Answer lookups by id with the id that was asked for
+		if obj, ok := out.(map[string]any); ok {
+			if id := r.PathValue("id"); id != "" {
+				if _, ok := obj["id"]; ok {
+					obj["id"] = id
+				}
+			}
+		}
+		writeJSON(w, http.StatusOK, out)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func splitIDs(s string) []string {
+	var ids []string
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// responseSchema returns the JSON schema of the 200 response of op.
+func (s *Server) responseSchema(op map[string]any) map[string]any {
+	responses, _ := op["responses"].(map[string]any)
+	resp, _ := s.resolve(responses["200"]).(map[string]any)
+	content, _ := resp["content"].(map[string]any)
+	media, _ := content["application/json"].(map[string]any)
+	schema, _ := s.resolve(media["schema"]).(map[string]any)
+	return schema
+}
+
+// resolve follows a local $ref such as #/components/schemas/PodcastFull.
+func (s *Server) resolve(node any) any {
+	for range 32 {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return node
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return node
+		}
+		var target any = s.doc
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			parent, _ := target.(map[string]any)
+			target = parent[part]
+		}
+		node = target
+	}
+	return nil
+}
+
+// maxDepth bounds how deeply nested objects are generated.
+const maxDepth = 6
+
+// generator builds the response to one request.
+type generator struct {
+	s   *Server
+	rng *rand.Rand
+	ids []string // ids a batch request asked for
+}
+
+// value generates a value for schema, the property called name.
+func (g *generator) value(node any, name string, depth int) any {
+	schema, _ := g.s.resolve(node).(map[string]any)
+	if schema == nil {
+		return nil
+	}
+	if variants, ok := firstOf(schema, "oneOf", "anyOf"); ok {
+		variant := variants[0]
+		if g.s.opts.Fake {
+			variant = variants[g.rng.IntN(len(variants))]
+		}
+		return g.value(variant, name, depth)
+	}
+	if parts, ok := schema["allOf"].([]any); ok {
+		merged := map[string]any{}
+		for _, part := range parts {
+			if obj, ok := g.value(part, name, depth).(map[string]any); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+
+	if !g.s.opts.Fake {
+		if example, ok := schema["example"]; ok {
+			return example
+		}
+		if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+			return examples[0]
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[g.rng.IntN(len(enum))]
+	}
+
+	switch typeName(schema) {
+	case "object":
+		return g.object(schema, depth)
+	case "array":
+		return g.array(schema, name, depth)
+	case "integer":
+		return g.integer(schema, name)
+	case "number":
+		return round2(g.rng.Float64() * 100)
+	case "boolean":
+		return g.rng.IntN(2) == 1
+	default:
+		return g.string(schema, name)
+	}
+}
+
+func firstOf(schema map[string]any, keys ...string) ([]any, bool) {
+	for _, key := range keys {
+		if variants, ok := schema[key].([]any); ok && len(variants) > 0 {
+			return variants, true
+		}
+	}
+	return nil, false
+}
+
+func (g *generator) object(schema map[string]any, depth int) map[string]any {
+	obj := map[string]any{}
+	props, _ := schema["properties"].(map[string]any)
+	if depth >= maxDepth {
+		return obj
+	}
+	// Generate in a fixed order so that the same seed gives the same data
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		obj[name] = g.value(props[name], name, depth+1)
+	}
+	return obj
+}
+
+func (g *generator) array(schema map[string]any, name string, depth int) []any {
+	items := schema["items"]
+	if depth >= maxDepth || items == nil {
+		return []any{}
+	}
+	// A batch lookup gets one result per id it asked for
+	if depth == 1 && len(g.ids) > 0 {
+		if item, ok := g.s.resolve(items).(map[string]any); ok {
+			if props, ok := item["properties"].(map[string]any); ok && props["id"] != nil {
+				out := make([]any, len(g.ids))
+				for i, id := range g.ids {
+					obj := g.object(item, depth)
+					obj["id"] = id
+					out[i] = obj
+				}
+				return out
+			}
+		}
+	}
+	n := 1 + g.rng.IntN(3)
+	if g.s.opts.Fake {
+		n = 2 + g.rng.IntN(4)
+	}
+	out := make([]any, n)
+	for i := range out {
+		out[i] = g.value(items, name, depth+1)
+	}
+	return out
+}
+
+func (g *generator) integer(schema map[string]any, name string) int64 {
+	lo, hi := int64(0), int64(1000)
+	if v, ok := schema["minimum"].(int); ok {
+		lo = int64(v)
+	}
+	if v, ok := schema["maximum"].(int); ok {
+		hi = int64(v)
+	}
+	switch {
+	case strings.HasSuffix(name, "_ms"):
+		// Some time in the three years before 2025-01-01
+		return 1735689600000 - g.rng.Int64N(3*365*24*3600*1000)
+	case strings.HasSuffix(name, "_sec"):
+		return 60 + g.rng.Int64N(7200)
+	}
+	if hi <= lo {
+		return lo
+	}
+	return lo + g.rng.Int64N(hi-lo+1)
+}
+
+var words = strings.Fields(`history science comedy news true crime daily business culture
+technology health society sports music fiction stories interviews weekly
+politics food travel education design startup science art film games`)
+
+var (
+	languages = []string{"English", "Spanish", "German", "French", "Japanese"}
+	countries = []string{"United States", "United Kingdom", "Canada", "Germany", "Australia"}
+)
+
+func (g *generator) string(schema map[string]any, name string) string {
+	pick := func(list []string) string { return list[g.rng.IntN(len(list))] }
+	phrase := func(n int) string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = pick(words)
+		}
+		return strings.Join(out, " ")
+	}
+	hex := func(n int) string {
+		const digits = "0123456789abcdef"
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = digits[g.rng.IntN(len(digits))]
+		}
+		return string(b)
+	}
+
+	if schema["format"] == "date-time" {
+		return time.UnixMilli(1735689600000 - g.rng.Int64N(365*24*3600*1000)).UTC().Format(time.RFC3339)
+	}
+	switch {
+	case name == "id" || strings.HasSuffix(name, "_id"):
+		return hex(32)
+	case name == "email":
+		return "host-" + hex(6) + "@example.com"
+	case name == "language":
+		return pick(languages)
+	case name == "country":
+		return pick(countries)
+	case strings.Contains(name, "url") || strings.Contains(name, "image") || strings.Contains(name, "thumbnail") ||
+		strings.Contains(name, "website") || name == "rss" || name == "audio" || name == "link":
+		return "https://example.com/" + name + "/" + hex(12)
+	case strings.Contains(name, "title") || strings.Contains(name, "name") || name == "publisher":
+		title := strings.Fields(phrase(2 + g.rng.IntN(3)))
+		for i, w := range title {
+			title[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+		return strings.Join(title, " ")
+	case strings.Contains(name, "description") || name == "notes":
+		sentence := phrase(12)
+		return strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+	}
+	if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+		return fmt.Sprint(examples[g.rng.IntN(len(examples))])
+	}
+	return phrase(2)
+}
+
+// typeName returns the JSON Schema type of schema, ignoring "null" in the
+// type lists of OpenAPI 3.1.
+func typeName(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if name, ok := v.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	// Some fields only give examples; generate values of the same kind
+	if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+		switch examples[0].(type) {
+		case int:
+			return "integer"
+		case float64:
+			return "number"
+		case bool:
+			return "boolean"
+		case []any:
+			return "array"
+		case map[string]any:
+			return "object"
+		}
+	}
+	return "string"
+}
+
+// round2 rounds f down to two decimals.
+func round2(f float64) float64 {
+	return float64(int64(f*100)) / 100
+}
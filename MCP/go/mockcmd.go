@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/mock"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+)
+
+// runMock implements `mcp-server mock`: it serves a fake Listen API built
+// from the embedded openapi.yaml until interrupted. Point API_BASE_URL at it
+// to run the tools offline.
+func runMock(args []string) {
+	fs := flag.NewFlagSet("mock", flag.ExitOnError)
+	port := fs.String("port", "8282", "port to listen on")
+	seed := fs.Uint64("seed", 1, "seed for generated data and injected errors")
+	fake := fs.Bool("fake", false, "generate varied fake data instead of the spec's examples")
+	errorRate := fs.Float64("error-rate", 0, "fraction of requests, 0 to 1, that fail")
+	errorStatuses := fs.String("error-statuses", "500", "comma-separated statuses for failed requests, e.g. 429,500,503")
+	latency := fs.Duration("latency", 0, "delay before every response, e.g. 200ms")
+	fs.Parse(args)
+
+	opts := mock.Options{
+		Seed:      *seed,
+		Fake:      *fake,
+		ErrorRate: *errorRate,
+		Latency:   *latency,
+	}
+	if *errorRate < 0 || *errorRate > 1 {
+		log.Fatalf("Invalid -error-rate %v: must be between 0 and 1", *errorRate)
+	}
+	for _, s := range strings.Split(*errorStatuses, ",") {
+		status, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || status < 400 || status > 599 {
+			log.Fatalf("Invalid -error-statuses %q: must be 4xx or 5xx statuses", *errorStatuses)
+		}
+		opts.ErrorStatuses = append(opts.ErrorStatuses, status)
+	}
+
+	handler, err := mock.NewServer(openapi.Spec, opts)
+	if err != nil {
+		log.Fatalf("Failed to start mock: %v", err)
+	}
+
+	addr := net.JoinHostPort("127.0.0.1", *port)
+	httpServer := &http.Server{Addr: addr, Handler: handler}
+	go func() {
+		log.Printf("Mock Listen API on http://%s (seed %d)", addr, *seed)
+		if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+			log.Fatalf("Mock server error: %v", err)
+		}
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	httpServer.Shutdown(ctx)
+}
@@ -0,0 +1,70 @@
+// Package openapi describes every Listen API operation in openapi.yaml. The
+// table in operations_gen.go is generated from the spec by cmd/toolgen and
+// drives the tool registry; run `go generate ./...` after updating the spec.
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"net/url"
+	"reflect"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+)
+
+//go:generate go run ../cmd/toolgen -spec ../../../openapi.yaml -ops operations_gen.go -client ../listenapi/operations_gen.go -embed openapi.yaml
+
+// Spec is a copy of openapi.yaml, for what needs more of the spec than the
+// Operations table, such as the mock server.
+//
+//go:embed openapi.yaml
+var Spec []byte
+
+// Operation is one path and method of the Listen API.
+type Operation struct {
+	ID          string // operationId
+	Method      string
+	Path        string
+	Tag         string
+	ToolName    string // MCP tool name, e.g. get_podcasts_id
+	Summary     string
+	Description string
+	Params      []Param
+
+	// Response is the models type of a successful response.
+	Response reflect.Type
+
+	// Call invokes the typed listenapi method for this operation. path holds
+	// the path parameters by name; query and form are passed through when
+	// the operation takes them.
+	Call func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error)
+}
+
+// Where a Param is sent.
+const (
+	InPath  = "path"
+	InQuery = "query"
+	InForm  = "form"
+)
+
+// Param is one path, query or form parameter of an Operation. Header
+// parameters such as the API key are not included.
+type Param struct {
+	Name        string
+	In          string // InPath, InQuery or InForm
+	Type        string // JSON Schema type: string, integer, number or boolean
+	Description string
+	Required    bool
+	Enum        []any
+	Default     any
+}
+
+// Lookup returns the operation with operationId id.
+func Lookup(id string) (Operation, bool) {
+	for _, op := range Operations {
+		if op.ID == id {
+			return op, true
+		}
+	}
+	return Operation{}, false
+}
@@ -0,0 +1,828 @@
+// Code generated by toolgen from openapi.yaml; DO NOT EDIT.
+
+package openapi
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+)
+
+// Operations lists every operation in openapi.yaml, ordered by path.
+var Operations = []Operation{
+	{
+		ID:          "getBestPodcasts",
+		Method:      "GET",
+		Path:        "/best_podcasts",
+		Tag:         "Directory API",
+		ToolName:    "get_best_podcasts",
+		Summary:     "Fetch a list of best podcasts by genre",
+		Description: "Get a list of curated best podcasts by genre,\nwhich are curated by Listen Notes staffs based on various signals from the Internet, e.g.,\ntop charts on other podcast platforms, recommendations from mainstream media,\nuser activities on listennotes.com...\nYou can get the genre ids from `GET /genres` endpoint.\nThis endpoint returns same data as https://www.listennotes.com/best-podcasts/\n",
+		Params: []Param{
+			{
+				Name:        "genre_id",
+				In:          InQuery,
+				Type:        "string",
+				Description: "You can get the id from `GET /genres`. If not specified, it'll be the overall best podcasts, which can be considered as a special genre.",
+			},
+			{
+				Name:        "page",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Page number of those podcasts in this genre.",
+			},
+			{
+				Name:        "region",
+				In:          InQuery,
+				Type:        "string",
+				Description: "Filter best podcasts by country/region.\nPlease note that podcasts that are \"best\" in a country/region may not be produced in that country/region.\nFor example, a podcast from the US may be very popular in Canada.\nYou can get the supported country codes (e.g., us, jp, gb...) from `GET /regions`.\nIf not specified, you'll get \"best podcasts\" in United States.\n",
+				Default:     "us",
+			},
+			{
+				Name:        "publisher_region",
+				In:          InQuery,
+				Type:        "string",
+				Description: "Filter best podcasts by the publisher's country/region.\nThis is to narrow down the results to include \"best podcasts\" produced in a specific country/region.\nYou can get the supported country codes (e.g., us, jp, gb...) from `GET /regions`.\nIf not specified, you'll get \"best podcasts\" produced in any country/region.\nIf you want to get a country/region's \"best podcasts\" that are also produced in that country/region,\nthen you need to specify both **region** and **publisher_region**,\ne.g., `region=jp` and `publisher_region=jp`.\n",
+			},
+			{
+				Name:        "language",
+				In:          InQuery,
+				Type:        "string",
+				Description: "Filter best podcasts by language.\nYou can get a list of supported languages (e.g., English, Chinese, Japanese...) from `GET /languages`.\nIf not specified, you'll get \"best podcasts\" in any language.\n",
+			},
+			{
+				Name:        "sort",
+				In:          InQuery,
+				Type:        "string",
+				Description: "How do you want to sort these podcasts?\nIf you'd like to sort by popularity, please use **listen_score**.\n",
+				Enum:        []any{"recent_added_first", "oldest_added_first", "recent_published_first", "oldest_published_first", "listen_score"},
+				Default:     "recent_added_first",
+			},
+			{
+				Name:        "safe_mode",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Whether or not to exclude podcasts with explicit language. 1 is yes, and 0 is no.",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+		},
+		Response: reflect.TypeFor[models.BestPodcastsResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetBestPodcasts(ctx, query)
+		},
+	},
+	{
+		ID:          "getCuratedPodcasts",
+		Method:      "GET",
+		Path:        "/curated_podcasts",
+		Tag:         "Directory API",
+		ToolName:    "get_curated_podcasts",
+		Summary:     "Fetch curated lists of podcasts",
+		Description: "A bunch of curated lists from online media. For each list, you'll get basic info of up to 5 podcasts. To get detailed meta data of all podcasts in a specific list, you need to use `GET /curated_podcasts/{id}`. We add new curated lists to the database on a daily basis.\n",
+		Params: []Param{
+			{
+				Name:        "page",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Page number of curated lists.",
+				Default:     1,
+			},
+		},
+		Response: reflect.TypeFor[models.GetCuratedPodcastsResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetCuratedPodcasts(ctx, query)
+		},
+	},
+	{
+		ID:          "getCuratedPodcastById",
+		Method:      "GET",
+		Path:        "/curated_podcasts/{id}",
+		Tag:         "Directory API",
+		ToolName:    "get_curated_podcasts_id",
+		Summary:     "Fetch a curated list of podcasts by id",
+		Description: "Get detailed meta data of all podcasts in a specific curated list.\nThis endpoint returns same data as https://www.listennotes.com/curated-podcasts/\n",
+		Params: []Param{
+			{
+				Name:        "id",
+				In:          InPath,
+				Type:        "string",
+				Description: "id for a specific curated list of podcasts. You can get the id from the response of `GET /search?type=curated` or `GET /curated_podcasts`.\n",
+				Required:    true,
+			},
+		},
+		Response: reflect.TypeFor[models.CuratedListFull](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetCuratedPodcastById(ctx, path["id"])
+		},
+	},
+	{
+		ID:          "getEpisodesInBatch",
+		Method:      "POST",
+		Path:        "/episodes",
+		Tag:         "Directory API",
+		ToolName:    "post_episodes",
+		Summary:     "Batch fetch basic meta data for episodes",
+		Description: "Batch fetch basic meta data for up to 10 episodes. This endpoint could be used to implement custom playlists for individual episodes. For detailed meta data of an individual episode, you need to use `GET /episodes/{id}`. This endpoint is available only in the PRO/ENTERPRISE plan.\n",
+		Params: []Param{
+			{
+				Name:        "ids",
+				In:          InForm,
+				Type:        "string",
+				Description: "Comma-separated list of episode ids.",
+				Required:    true,
+			},
+		},
+		Response: reflect.TypeFor[models.GetEpisodesInBatchResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetEpisodesInBatch(ctx, form)
+		},
+	},
+	{
+		ID:          "getEpisodeById",
+		Method:      "GET",
+		Path:        "/episodes/{id}",
+		Tag:         "Directory API",
+		ToolName:    "get_episodes_id",
+		Summary:     "Fetch detailed meta data for an episode by id",
+		Description: "Fetch detailed meta data for a specific episode.",
+		Params: []Param{
+			{
+				Name:        "id",
+				In:          InPath,
+				Type:        "string",
+				Description: "id for a specific episode. You can get episode id from using other endpoints, e.g., `GET /search`...",
+				Required:    true,
+			},
+			{
+				Name:        "show_transcript",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "To include the transcript of this episode or not? If it is 1, then include the transcript in the **transcript** field. The default value is 0 - we don't include transcript by default, because 1) it would make the response data very big, thus slow response time; 2) less than 1% of episodes have transcripts. The transcript field is available only in the PRO/ENTERPRISE plan.",
+				Default:     0,
+			},
+		},
+		Response: reflect.TypeFor[models.EpisodeFull](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetEpisodeById(ctx, path["id"], query)
+		},
+	},
+	{
+		ID:          "getEpisodeRecommendations",
+		Method:      "GET",
+		Path:        "/episodes/{id}/recommendations",
+		Tag:         "Directory API",
+		ToolName:    "get_episodes_id_recommendations",
+		Summary:     "Fetch recommendations for an episode",
+		Description: "Fetch up to 8 episode recommendations based on the given episode id.",
+		Params: []Param{
+			{
+				Name:        "id",
+				In:          InPath,
+				Type:        "string",
+				Description: "Episode id.",
+				Required:    true,
+			},
+			{
+				Name:        "safe_mode",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Whether or not to exclude podcasts with explicit language. 1 is yes, and 0 is no.",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+		},
+		Response: reflect.TypeFor[models.GetEpisodeRecommendationsResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetEpisodeRecommendations(ctx, path["id"], query)
+		},
+	},
+	{
+		ID:          "getGenres",
+		Method:      "GET",
+		Path:        "/genres",
+		Tag:         "Directory API",
+		ToolName:    "get_genres",
+		Summary:     "Fetch a list of podcast genres",
+		Description: "Get a list of podcast genres that are supported in Listen Notes.\nThe genre id can be passed to other endpoints as a parameter to get podcasts in a specific genre,\ne.g., `GET /best_podcasts`, `GET /search`...\nYou may want to cache the list of genres on the client side.\n",
+		Params: []Param{
+			{
+				Name:        "top_level_only",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Just show top level genres? If 1, yes, just show top level genres. If 0, no, show all genres.\n",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+		},
+		Response: reflect.TypeFor[models.GetGenresResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetGenres(ctx, query)
+		},
+	},
+	{
+		ID:          "justListen",
+		Method:      "GET",
+		Path:        "/just_listen",
+		Tag:         "Directory API",
+		ToolName:    "get_just_listen",
+		Summary:     "Fetch a random podcast episode",
+		Description: "Recently published episodes are more likely to be fetched. Good luck!",
+		Params:      []Param{},
+		Response:    reflect.TypeFor[models.EpisodeSimple](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.JustListen(ctx)
+		},
+	},
+	{
+		ID:          "getLanguages",
+		Method:      "GET",
+		Path:        "/languages",
+		Tag:         "Directory API",
+		ToolName:    "get_languages",
+		Summary:     "Fetch a list of supported languages for podcasts",
+		Description: "Get a list of languages that are supported in Listen Notes database. You can use the language string as query parameter in `GET /search`.\n",
+		Params:      []Param{},
+		Response:    reflect.TypeFor[models.GetLanguagesResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetLanguages(ctx)
+		},
+	},
+	{
+		ID:          "getPlaylists",
+		Method:      "GET",
+		Path:        "/playlists",
+		Tag:         "Playlist API",
+		ToolName:    "get_playlists",
+		Summary:     "Fetch a list of your playlists.",
+		Description: "This endpoint returns same data as listennotes.com/listen under your account.\nYou can use the **page** parameter to do pagination and fetch more playlists.\n",
+		Params: []Param{
+			{
+				Name:        "sort",
+				In:          InQuery,
+				Type:        "string",
+				Description: "How do you want to sort playlists?\n",
+				Enum:        []any{"recent_added_first", "oldest_added_first", "name_a_to_z", "name_z_to_a"},
+				Default:     "recent_added_first",
+			},
+			{
+				Name:        "page",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Page number of playlists.\n",
+				Default:     1,
+			},
+		},
+		Response: reflect.TypeFor[models.PlaylistsResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetPlaylists(ctx, query)
+		},
+	},
+	{
+		ID:          "getPlaylistById",
+		Method:      "GET",
+		Path:        "/playlists/{id}",
+		Tag:         "Playlist API",
+		ToolName:    "get_playlists_id",
+		Summary:     "Fetch a playlist's info and items (i.e., episodes or podcasts).",
+		Description: "A playlist can be an episode list (i.e., all items are episodes) or a podcast list (i.e., all items are podcasts),\nwhich is essentially the same as those created via listennotes.com/listen/.\nThis endpoint fetches a list of items (i.e., episodes or podcasts) in the playlist.\nYou can use the **last_pub_date_ms** parameter to do pagination and fetch more items.\nA playlist can be **public** (discoverable on ListenNotes.com),\n**unlisted** (accessible to anyone who knows the playlist id),\nor **private** (accessible to its owner).\nYou can fetch all playlists created by you, and **public** / **unlisted** playlists created by others.\n",
+		Params: []Param{
+			{
+				Name:        "id",
+				In:          InPath,
+				Type:        "string",
+				Description: "Playlist id (always 11 characters, e.g., m1pe7z60bsw).\nYou can get the podcast id from the url of a playlist, e.g.,\nm1pe7z60bsw is the playlist id of listennotes.com/listen/podcasts-about-podcasting-m1pe7z60bsw\n",
+				Required:    true,
+			},
+			{
+				Name:        "type",
+				In:          InQuery,
+				Type:        "string",
+				Description: "The type of this playlist, which should be either **episode_list** or **podcast_list**.\n",
+				Enum:        []any{"episode_list", "podcast_list"},
+				Default:     "episode_list",
+			},
+			{
+				Name:        "last_timestamp_ms",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "For playlist items pagination.\nIt's the value of **last_timestamp_ms** from the response of last request.\nIf it's 0 or not specified, just return the latest or the oldest 20 items,\ndepending on the value of the **sort** parameter.\n",
+				Default:     0,
+			},
+			{
+				Name:        "sort",
+				In:          InQuery,
+				Type:        "string",
+				Description: "How do you want to sort playlist items?\n",
+				Enum:        []any{"recent_added_first", "oldest_added_first", "recent_published_first", "oldest_published_first"},
+				Default:     "recent_added_first",
+			},
+		},
+		Response: reflect.TypeFor[models.PlaylistResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetPlaylistById(ctx, path["id"], query)
+		},
+	},
+	{
+		ID:          "getPodcastsInBatch",
+		Method:      "POST",
+		Path:        "/podcasts",
+		Tag:         "Directory API",
+		ToolName:    "post_podcasts",
+		Summary:     "Batch fetch basic meta data for podcasts",
+		Description: "Batch fetch basic meta data for up to 10 podcasts.\nThis endpoint could be used to build something like OPML import,\nallowing users to import a bunch of podcasts via rss urls.\nFor detailed meta data (including episodes) of an individual podcast, you need to use `GET /podcasts/{id}`. This endpoint is available only in the PRO/ENTERPRISE plan.\n",
+		Params: []Param{
+			{
+				Name:        "ids",
+				In:          InForm,
+				Type:        "string",
+				Description: "Comma-separated list of podcast ids.",
+			},
+			{
+				Name:        "itunes_ids",
+				In:          InForm,
+				Type:        "string",
+				Description: "Comma-separated Apple Podcasts (iTunes) ids, e.g., 659155419",
+			},
+			{
+				Name:        "next_episode_pub_date",
+				In:          InForm,
+				Type:        "integer",
+				Description: "For latest episodes pagination. It's the value of **next_episode_pub_date** from the response of last request. If not specified, just return latest 15 episodes.\n",
+			},
+			{
+				Name:        "rsses",
+				In:          InForm,
+				Type:        "string",
+				Description: "Comma-separated rss urls.",
+			},
+			{
+				Name:        "show_latest_episodes",
+				In:          InForm,
+				Type:        "integer",
+				Description: "Whether or not to fetch up to 15 latest episodes from these podcasts, sorted by pub_date. 1 is yes, and 0 is no.\n",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+			{
+				Name:        "spotify_ids",
+				In:          InForm,
+				Type:        "string",
+				Description: "Comma-separated Spotify ids, e.g., 3DDfEsKDIDrTlnPOiG4ZF4",
+			},
+		},
+		Response: reflect.TypeFor[models.GetPodcastsInBatchResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetPodcastsInBatch(ctx, form)
+		},
+	},
+	{
+		ID:          "getPodcastsByDomainName",
+		Method:      "GET",
+		Path:        "/podcasts/domains/{domain_name}",
+		Tag:         "Insights API",
+		ToolName:    "get_podcasts_domains_domain_name",
+		Summary:     "Fetch podcasts by a publisher's domain name",
+		Description: "Fetch podcasts by a publisher's domain name, e.g., nytimes.com, wondery.com, npr.org...\nEach request will return up to 10 podcasts. You can use the `page` parameter to paginate.\n",
+		Params: []Param{
+			{
+				Name:        "domain_name",
+				In:          InPath,
+				Type:        "string",
+				Description: "A publisher's domain name, e.g., nytimes.com, wondery.com, npr.org...",
+				Required:    true,
+			},
+			{
+				Name:        "page",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Page number of the podcasts from this domain name",
+				Default:     1,
+			},
+		},
+		Response: reflect.TypeFor[models.PodcastDomainResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetPodcastsByDomainName(ctx, path["domain_name"], query)
+		},
+	},
+	{
+		ID:          "submitPodcast",
+		Method:      "POST",
+		Path:        "/podcasts/submit",
+		Tag:         "Podcaster API",
+		ToolName:    "post_podcasts_submit",
+		Summary:     "Submit a podcast to Listen Notes database",
+		Description: "Podcast hosting services can use this endpoint to help your users directly submit a new podcast to Listen Notes database. If the podcast doesn't exist in the database, \"status\" in the response will be \"in review\", and we'll review it within 12 hours. If the podcast exists, \"status\" in the response will be \"found\". If this submission is rejected, \"status\" in the response will be \"rejected\". You can use `POST /podcasts` to check if multiple podcasts exist in the database. If you want to get a notification once the podcast is accepted, you can either specify the \"email\" parameter or configure a webhook url in the dashboard: listennotes.com/api/dashboard/#webhooks\n",
+		Params: []Param{
+			{
+				Name:        "email",
+				In:          InForm,
+				Type:        "string",
+				Description: "A valid email address. If **email** is specified, then we'll notify this email address once the podcast is accepted.",
+			},
+			{
+				Name:        "rss",
+				In:          InForm,
+				Type:        "string",
+				Description: "A valid podcast rss url.",
+				Required:    true,
+			},
+		},
+		Response: reflect.TypeFor[models.SubmitPodcastResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.SubmitPodcast(ctx, form)
+		},
+	},
+	{
+		ID:          "deletePodcastById",
+		Method:      "DELETE",
+		Path:        "/podcasts/{id}",
+		Tag:         "Podcaster API",
+		ToolName:    "delete_podcasts_id",
+		Summary:     "Request to delete a podcast",
+		Description: "Podcast hosting services can use this endpoint to streamline the process of podcast deletion on behave of their users (podcasters). We will review the deletion request within 12 hours. If the podcast is already deleted, the \"status\" field in the response will be \"deleted\". Otherwise, the status field will be \"in review\". If you want to get a notification once the podcast is deleted, you can configure a webhook url in the dashboard: listennotes.com/api/dashboard/#webhooks\n",
+		Params: []Param{
+			{
+				Name:        "id",
+				In:          InPath,
+				Type:        "string",
+				Description: "Podcast id. You can get podcast id from using other endpoints, e.g., `GET /search`, `GET /best_podcasts`...",
+				Required:    true,
+			},
+			{
+				Name:        "reason",
+				In:          InQuery,
+				Type:        "string",
+				Description: "The reason why this podcast should be deleted, e.g., copyright violation, the podcaster wants to delete it... You can put \"testing\" here to indicate that you are testing this endpoint, so we will not actually delete the podcast.",
+			},
+		},
+		Response: reflect.TypeFor[models.DeletePodcastResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.DeletePodcastById(ctx, path["id"], query)
+		},
+	},
+	{
+		ID:          "getPodcastById",
+		Method:      "GET",
+		Path:        "/podcasts/{id}",
+		Tag:         "Directory API",
+		ToolName:    "get_podcasts_id",
+		Summary:     "Fetch detailed meta data and episodes for a podcast by id",
+		Description: "Fetch detailed meta data and episodes for a specific podcast (up to 10 episodes each time).\nYou can use the **next_episode_pub_date** parameter to do pagination and fetch more episodes.\n",
+		Params: []Param{
+			{
+				Name:        "id",
+				In:          InPath,
+				Type:        "string",
+				Description: "Podcast id. You can get podcast id from using other endpoints, e.g., `GET /search`, `GET /best_podcasts`...",
+				Required:    true,
+			},
+			{
+				Name:        "next_episode_pub_date",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "For episodes pagination. It's the value of **next_episode_pub_date** from the response of last request. If not specified, just return latest 10 episodes or oldest 10 episodes, depending on the value of the **sort** parameter.\n",
+			},
+			{
+				Name:        "sort",
+				In:          InQuery,
+				Type:        "string",
+				Description: "How do you want to sort the episodes of this podcast?\n",
+				Enum:        []any{"recent_first", "oldest_first"},
+				Default:     "recent_first",
+			},
+		},
+		Response: reflect.TypeFor[models.PodcastFull](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetPodcastById(ctx, path["id"], query)
+		},
+	},
+	{
+		ID:          "getPodcastAudience",
+		Method:      "GET",
+		Path:        "/podcasts/{id}/audience",
+		Tag:         "Insights API",
+		ToolName:    "get_podcasts_id_audience",
+		Summary:     "Fetch audience demographics for a podcast",
+		Description: "Fetch audience demographics for a podcast - 1) directly measured on the Listen Notes platform; 2) only supports audience breakdown by regions for now; 3) not every podcast has data.",
+		Params: []Param{
+			{
+				Name:        "id",
+				In:          InPath,
+				Type:        "string",
+				Description: "Podcast id.",
+				Required:    true,
+			},
+		},
+		Response: reflect.TypeFor[models.PodcastAudienceResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetPodcastAudience(ctx, path["id"])
+		},
+	},
+	{
+		ID:          "getPodcastRecommendations",
+		Method:      "GET",
+		Path:        "/podcasts/{id}/recommendations",
+		Tag:         "Directory API",
+		ToolName:    "get_podcasts_id_recommendations",
+		Summary:     "Fetch recommendations for a podcast",
+		Description: "Fetch up to 8 podcast recommendations based on the given podcast id.",
+		Params: []Param{
+			{
+				Name:        "id",
+				In:          InPath,
+				Type:        "string",
+				Description: "Podcast id.",
+				Required:    true,
+			},
+			{
+				Name:        "safe_mode",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Whether or not to exclude podcasts with explicit language. 1 is yes, and 0 is no.",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+		},
+		Response: reflect.TypeFor[models.GetPodcastRecommendationsResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetPodcastRecommendations(ctx, path["id"], query)
+		},
+	},
+	{
+		ID:          "getRegions",
+		Method:      "GET",
+		Path:        "/regions",
+		Tag:         "Directory API",
+		ToolName:    "get_regions",
+		Summary:     "Fetch a list of supported countries/regions for best podcasts",
+		Description: "It returns a dictionary of country codes (e.g., us, gb...) & country names (United States, United Kingdom...). The country code is used in the query parameter **region** of `GET /best_podcasts`.\n",
+		Params:      []Param{},
+		Response:    reflect.TypeFor[models.GetRegionsResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetRegions(ctx)
+		},
+	},
+	{
+		ID:          "getRelatedSearches",
+		Method:      "GET",
+		Path:        "/related_searches",
+		Tag:         "Search API",
+		ToolName:    "get_related_searches",
+		Summary:     "Fetch related search terms",
+		Description: "Suggest related search terms. The results are more comprehensive than from `GET /typeahead`. This endpoint is available only in the PRO/ENTERPRISE plan.",
+		Params: []Param{
+			{
+				Name:        "q",
+				In:          InQuery,
+				Type:        "string",
+				Description: "Search term, e.g., person, place, topic...\n",
+				Required:    true,
+			},
+		},
+		Response: reflect.TypeFor[models.RelatedSearchesResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetRelatedSearches(ctx, query)
+		},
+	},
+	{
+		ID:          "search",
+		Method:      "GET",
+		Path:        "/search",
+		Tag:         "Search API",
+		ToolName:    "get_search",
+		Summary:     "Full-text search",
+		Description: "Full-text search on episodes, podcasts, or curated lists of podcasts.\nUse the `offset` parameter to paginate through search results.\nThe FREE plan allows to see up to 30 search results (or `offset` < 30) per query.\nThe PRO plan allows to see up to 300 search results (or `offset` < 300) per query.\nThe ENTERPRISE plan allows to see up to 10,000 search results (or `offset` < 10000) per query.\n",
+		Params: []Param{
+			{
+				Name:        "q",
+				In:          InQuery,
+				Type:        "string",
+				Description: "Search term, e.g., person, place, topic... You can use double quotes to do verbatim match, e.g., \"game of thrones\". Otherwise, it's fuzzy search.\n",
+				Required:    true,
+			},
+			{
+				Name:        "sort_by_date",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Sort by date or not? If 0, then sort by relevance. If 1, then sort by date.\n",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+			{
+				Name:        "type",
+				In:          InQuery,
+				Type:        "string",
+				Description: "What type of contents do you want to search for? \n",
+				Enum:        []any{"episode", "podcast", "curated"},
+				Default:     "episode",
+			},
+			{
+				Name:        "offset",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Offset for search results, for pagination. You'll use **next_offset** from response for this parameter.\n",
+				Default:     0,
+			},
+			{
+				Name:        "len_min",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Minimum audio length in minutes. Applicable only when **type** parameter is **episode** or **podcast**.\nIf **type** parameter is **episode**, it's for audio length of an episode.\nIf **type** parameter is **podcast**, it's for average audio length of all episodes in a podcast.\n",
+				Default:     0,
+			},
+			{
+				Name:        "len_max",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Maximum audio length in minutes. Applicable only when **type** parameter is **episode** or **podcast**.\nIf **type** parameter is **episode**, it's for audio length of an episode.\nIf **type** parameter is **podcast**, it's for average audio length of all episodes in a podcast.\n",
+			},
+			{
+				Name:        "episode_count_min",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Minimum number of episodes. Applicable only when type parameter is **podcast**.\n",
+			},
+			{
+				Name:        "episode_count_max",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Maximum number of episodes. Applicable only when type parameter is **podcast**.\n",
+			},
+			{
+				Name:        "update_freq_min",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Minimum update frequency in hours (how frequently does a podcast release a new episode). For example, if you want to find \"weekly\" podcasts, then you can set **update_freq_min**=144 hours (or 6 days) and **update_freq_max**=192 hours (or 8 days). Applicable only when type parameter is **podcast**.\n",
+			},
+			{
+				Name:        "update_freq_max",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Maximum update frequency in hours (how frequently does a podcast release a new episode). For example, if you want to find \"weekly\" podcasts, then you can set **update_freq_min**=144 hours (or 6 days) and **update_freq_max**=192 hours (or 8 days). Applicable only when type parameter is **podcast**.\n",
+			},
+			{
+				Name:        "genre_ids",
+				In:          InQuery,
+				Type:        "string",
+				Description: "A comma-delimited string of a list of genre ids. If not specified, then all genres are included. You can find the id and the name of all genres from `GET /genres`. It works only when **type** is *episode* or *podcast*.\n",
+			},
+			{
+				Name:        "published_before",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Only show episodes/podcasts/curated lists published before this timestamp (in milliseconds). If **published_before** & **published_after** are used at the same time, **published_before** should be bigger than **published_after**.\n",
+			},
+			{
+				Name:        "published_after",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Only show episodes/podcasts/curated lists published after this timestamp (in milliseconds). If **published_before** & **published_after** are used at the same time, **published_before** should be bigger than **published_after**.\n",
+				Default:     0,
+			},
+			{
+				Name:        "only_in",
+				In:          InQuery,
+				Type:        "string",
+				Description: "A comma-delimited string to search only in specific fields. Allowed values are title, description, author, and audio. If not specified, then search every fields.\n",
+				Default:     "title,description,author,audio",
+			},
+			{
+				Name:        "language",
+				In:          InQuery,
+				Type:        "string",
+				Description: "Limit search results to a specific language. If not specified, it'll be any language. You can get a list of supported languages from `GET /languages`. It works only when **type** is *episode* or *podcast*.\n",
+			},
+			{
+				Name:        "region",
+				In:          InQuery,
+				Type:        "string",
+				Description: "Limit search results to a specific region (e.g., us, gb, in...). If not specified, it'll be any region. You can get the supported country codes from `GET /regions`. It works only when **type** is *episode* or *podcast*.\n",
+			},
+			{
+				Name:        "ocid",
+				In:          InQuery,
+				Type:        "string",
+				Description: "A comma-delimited string of podcast ids (up to 5 podcasts) - you can get a podcast id from the **podcast_id** field in response. This parameter is to limit search results from only a few specific podcasts. It works only when **type** is *episode*.\n",
+			},
+			{
+				Name:        "ncid",
+				In:          InQuery,
+				Type:        "string",
+				Description: "A comma-delimited string of podcast ids (up to 5 podcasts) - you can get a podcast id from the **podcast_id** field in response. This parameter is to exclude search results of a few specific podcasts. It works only when **type** is *episode*.\n",
+			},
+			{
+				Name:        "safe_mode",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Whether or not to exclude podcasts/episodes with explicit language. 1 is yes and 0 is no. It works only when **type** is *episode* or *podcast*.\n",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+			{
+				Name:        "unique_podcasts",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Whether or not to keep only one episode per podcast in search results. 1 is yes and 0 is no. It works only when **type** is *episode*.\n",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+			{
+				Name:        "page_size",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "The maximum number of search results per page. A valid value should be an integer between 1 and 10 (inclusive).\n",
+				Default:     10,
+			},
+		},
+		Response: reflect.TypeFor[models.SearchResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.Search(ctx, query)
+		},
+	},
+	{
+		ID:          "spellcheck",
+		Method:      "GET",
+		Path:        "/spellcheck",
+		Tag:         "Search API",
+		ToolName:    "get_spellcheck",
+		Summary:     "Spell check on a search term",
+		Description: "Suggest a list of words that correct the spelling errors of a search term. This endpoint is available only in the PRO/ENTERPRISE plan.",
+		Params: []Param{
+			{
+				Name:        "q",
+				In:          InQuery,
+				Type:        "string",
+				Description: "Search term, e.g., person, place, topic...\n",
+				Required:    true,
+			},
+		},
+		Response: reflect.TypeFor[models.SpellCheckResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.Spellcheck(ctx, query)
+		},
+	},
+	{
+		ID:          "getTrendingSearches",
+		Method:      "GET",
+		Path:        "/trending_searches",
+		Tag:         "Search API",
+		ToolName:    "get_trending_searches",
+		Summary:     "Fetch trending search terms",
+		Description: "Fetch up to 10 most recent trending search terms on the Listen Notes platform.",
+		Params:      []Param{},
+		Response:    reflect.TypeFor[models.TrendingSearchesResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.GetTrendingSearches(ctx)
+		},
+	},
+	{
+		ID:          "typeahead",
+		Method:      "GET",
+		Path:        "/typeahead",
+		Tag:         "Search API",
+		ToolName:    "get_typeahead",
+		Summary:     "Typeahead search",
+		Description: "Suggest search terms, podcast genres, and podcasts.",
+		Params: []Param{
+			{
+				Name:        "q",
+				In:          InQuery,
+				Type:        "string",
+				Description: "Search term, e.g., person, place, topic... You can use double quotes to do verbatim match, e.g., \"game of thrones\". Otherwise, it's fuzzy search.\n",
+				Required:    true,
+			},
+			{
+				Name:        "show_podcasts",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Autosuggest podcasts. This only searches podcast title and publisher and returns very limited info of 5 podcasts. 1 is yes, 0 is no. It's a bit slow to autosuggest podcasts, so we turn it off by default. If show_podcasts=1, you can also pass iTunes id (e.g., 474722933) to the q parameter to fetch podcast meta data.\n",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+			{
+				Name:        "show_genres",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Whether or not to autosuggest genres. 1 is yes, 0 is no.\n",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+			{
+				Name:        "safe_mode",
+				In:          InQuery,
+				Type:        "integer",
+				Description: "Whether or not to exclude podcasts/episodes with explicit language. 1 is yes and 0 is no. It works only when **show_podcasts** is *1*.\n",
+				Enum:        []any{0, 1},
+				Default:     0,
+			},
+		},
+		Response: reflect.TypeFor[models.TypeaheadResponse](),
+		Call: func(ctx context.Context, client *listenapi.Client, path map[string]string, query, form url.Values) (any, error) {
+			return client.Typeahead(ctx, query)
+		},
+	},
+}
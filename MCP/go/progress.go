@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// methodNotificationProgress is the method of progress notifications, which
+// mcp-go does not define.
+const methodNotificationProgress = "notifications/progress"
+
+// progressMiddleware reports the progress of tool calls that make several
+// upstream requests, the batch tools and list tools asked for several pages,
+// to clients that asked for it with a progress token. Progress counts the
+// lookups answered so far, or the pages fetched; tools that make a single
+// request report nothing.
+func progressMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		meta := request.Params.Meta
+		if meta == nil || meta.ProgressToken == nil {
+			return next(ctx, request)
+		}
+		srv := server.ServerFromContext(ctx)
+		if srv == nil {
+			return next(ctx, request)
+		}
+
+		token := meta.ProgressToken
+		notifyCtx := ctx
+		tracker, _ := ctx.Value(notificationTrackerKey{}).(*notificationTracker)
+		ctx = listenapi.WithProgress(ctx, func(p listenapi.Progress) {
+			params := map[string]any{
+				"progressToken": token,
+				"progress":      p.Lookups,
+				"total":         p.TotalLookups,
+				"message":       fmt.Sprintf("Fetched %d of %d lookups in %d of %d requests", p.Lookups, p.TotalLookups, p.Requests, p.TotalRequests),
+			}
+			if p.Paged {
+				params["progress"], params["total"] = p.Requests, p.TotalRequests
+				params["message"] = fmt.Sprintf("Fetched %d of %d pages, %d items so far", p.Requests, p.TotalRequests, p.Items)
+			}
+			// A client that stopped listening misses the update
+			err := srv.SendNotificationToClient(notifyCtx, methodNotificationProgress, params)
+			if err == nil && tracker != nil {
+				tracker.sent()
+			}
+		})
+		if tracker != nil {
+			defer tracker.wait()
+		}
+		return next(ctx, request)
+	}
+}
+
+// notificationFlushTimeout bounds how long a tool call waits for its
+// progress notifications to be written before it returns its result.
+const notificationFlushTimeout = time.Second
+
+type notificationTrackerKey struct{}
+
+// notificationTracker counts the progress notifications sent during a
+// Streamable HTTP request that are not written to its response yet. mcp-go
+// drops any that are not written when the result is, which would be the
+// last progress update of many calls; the call waits for them instead.
+type notificationTracker struct {
+	mu      sync.Mutex
+	pending int           // sent and not written yet; below zero while a write beats its send
+	idle    chan struct{} // closed when pending drops to zero, if wait is waiting
+}
+
+// sent records that a progress notification was queued for the response.
+func (t *notificationTracker) sent() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending++
+}
+
+// written records that a progress notification was written to the
+// response, and wakes wait once none is left.
+func (t *notificationTracker) written() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending--
+	if t.pending <= 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// wait returns once every notification sent has been written, or after
+// notificationFlushTimeout. It is called after the last one is sent.
+func (t *notificationTracker) wait() {
+	t.mu.Lock()
+	if t.pending <= 0 {
+		t.mu.Unlock()
+		return
+	}
+	idle := make(chan struct{})
+	t.idle = idle
+	t.mu.Unlock()
+
+	timer := time.NewTimer(notificationFlushTimeout)
+	defer timer.Stop()
+	select {
+	case <-idle:
+	case <-timer.C:
+	}
+}
+
+// trackNotifications returns w and r set up to count the notifications
+// written in response to r, for progressMiddleware.
+func trackNotifications(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request) {
+	tracker := &notificationTracker{}
+	ctx := context.WithValue(r.Context(), notificationTrackerKey{}, tracker)
+	return &trackingWriter{ResponseWriter: w, tracker: tracker}, r.WithContext(ctx)
+}
+
+// trackingWriter counts the notifications written through it by their
+// flushes. The Streamable HTTP transport of mcp-go flushes the response
+// once after writing each notification and never while writing the result,
+// and progress notifications are the only ones this server sends during a
+// call, so every flush is one of them written. TestProgressBeforeResult
+// holds mcp-go to this.
+type trackingWriter struct {
+	http.ResponseWriter
+	tracker *notificationTracker
+}
+
+func (w *trackingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+	w.tracker.written()
+}
+
+func (w *trackingWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
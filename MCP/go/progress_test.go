@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestProgressBeforeResult holds the Streamable HTTP transport of mcp-go to
+// what trackingWriter counts on: a flush after each notification it writes,
+// so that every progress update is written ahead of the result without the
+// call waiting out notificationFlushTimeout.
+func TestProgressBeforeResult(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	upstream.respond(http.StatusOK, `{"episodes":[]}`)
+	srv := serveHTTP(t, testConfig(upstream.URL), "HTTP")
+	c, err := startSession(t, srv, false, nil)
+	if err != nil {
+		t.Fatalf("starting the session: %v", err)
+	}
+
+	ids := make([]string, 25)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("e%d", i+1)
+	}
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"get_episodes_batch","arguments":{"ids":%q},"_meta":{"progressToken":"batch-1"}}}`,
+		strings.Join(ids, ","))
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set(server.HeaderKeySessionID, sessionID(c))
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var methods []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var message struct {
+			Method string `json:"method"`
+			ID     any    `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(data), &message); err != nil {
+			t.Fatalf("event %q is not JSON: %v", data, err)
+		}
+		if message.ID != nil {
+			message.Method = "result"
+		}
+		methods = append(methods, message.Method)
+	}
+	elapsed := time.Since(start)
+
+	want := []string{methodNotificationProgress, methodNotificationProgress, methodNotificationProgress, "result"}
+	if !reflect.DeepEqual(methods, want) {
+		t.Errorf("response streamed %v, want %v", methods, want)
+	}
+	if elapsed >= notificationFlushTimeout {
+		t.Errorf("call took %v, want it not to wait out the %v flush timeout", elapsed, notificationFlushTimeout)
+	}
+}
+
+func TestNotificationTrackerWait(t *testing.T) {
+	tracker := &notificationTracker{}
+	start := time.Now()
+	tracker.wait()
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("wait with nothing sent took %v, want no wait", elapsed)
+	}
+
+	// A write that beats its send leaves nothing to wait for
+	tracker.written()
+	tracker.sent()
+	start = time.Now()
+	tracker.wait()
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("wait after the write took %v, want no wait", elapsed)
+	}
+
+	// wait wakes as soon as the last notification is written
+	tracker.sent()
+	tracker.sent()
+	time.AfterFunc(10*time.Millisecond, func() { tracker.written() })
+	time.AfterFunc(30*time.Millisecond, func() { tracker.written() })
+	start = time.Now()
+	tracker.wait()
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("wait returned after %v, want right after the second write", elapsed)
+	}
+
+	// and gives up on notifications that are never written
+	tracker.sent()
+	start = time.Now()
+	tracker.wait()
+	if elapsed := time.Since(start); elapsed < notificationFlushTimeout {
+		t.Errorf("wait returned after %v, want it to wait %v", elapsed, notificationFlushTimeout)
+	}
+}
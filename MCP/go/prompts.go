@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// promptSpec is a ready-made research workflow. Its template, rendered with
+// the prompt's arguments, is the user message that starts it; it names the
+// tools the model should call and in which order.
+type promptSpec struct {
+	Name        string
+	Description string
+	Args        []promptArg
+	Template    string // text/template over the arguments by name
+}
+
+// promptArg is an argument of a prompt. Default is used when an optional
+// argument is not given. Integer arguments must be between 1 and Max.
+type promptArg struct {
+	Name        string
+	Description string
+	Required    bool
+	Default     string
+	Integer     bool
+	Max         int
+}
+
+// researchPrompts are the workflows offered to clients, e.g. as slash
+// commands.
+var researchPrompts = []promptSpec{
+	{
+		Name:        "find_guests",
+		Description: "Find people who have been guests on podcasts about a topic, with the episodes they appeared in.",
+		Args: []promptArg{
+			{Name: "topic", Description: "The topic the guests should be experts on, e.g. climate tech", Required: true},
+			{Name: "language", Description: "Only consider podcasts in this language, e.g. English"},
+			{Name: "genre_ids", Description: "Only consider podcasts in these genres, a comma-separated list of genre ids, e.g. 93,127"},
+		},
+		Template: `Find podcast guests who are experts on {{printf "%q" .topic}}.
+
+1. Call get_search with q={{printf "%q" .topic}}, type="episode" and only_in="title,description"{{with .language}}, language={{printf "%q" .}}{{end}}{{with .genre_ids}}, genre_ids={{printf "%q" .}}{{end}} to find interviews on the topic. Fetch a second page with offset if the first one is thin.
+2. From the episode titles and descriptions, pick out the guests: the people being interviewed, not the hosts.
+3. For the two or three shows that come up most, call get_podcasts_id_recommendations with their podcast ids, then get_search with the topic again restricted to those shows (ocid) to find more guests.
+
+Answer with a table of up to 10 guests: name, what they are known for, and the episodes they appeared in (episode title, podcast, publish date, Listen Notes URL). Say if a name only appears once and may be a host.`,
+	},
+	{
+		Name:        "competitive_landscape",
+		Description: "Map the leading podcasts of a genre in a region: who dominates, how they compare, and where the gaps are.",
+		Args: []promptArg{
+			{Name: "genre_id", Description: "Genre id, e.g. 93 for Business; see the listennotes://genres resource", Required: true},
+			{Name: "region", Description: "Region code, e.g. us or gb; see the listennotes://regions resource", Default: "us"},
+			{Name: "publisher_region", Description: "Only consider podcasts whose publisher is in this region, e.g. gb"},
+		},
+		Template: `Describe the competitive landscape of podcasts in genre {{.genre_id}} in region {{printf "%q" .region}}{{with .publisher_region}}, from publishers in region {{printf "%q" .}}{{end}}.
+
+1. Call get_best_podcasts with genre_id={{.genre_id}}, region={{printf "%q" .region}}{{with .publisher_region}} and publisher_region={{printf "%q" .}}{{end}}, pages 1 and 2.
+2. For the five top shows, call get_podcasts_id_recommendations to find close competitors that are not in the list yet.
+3. Where it helps the comparison, call get_search with type="podcast" and the genre's main themes (genre_ids={{.genre_id}}) to find newer or niche shows.
+
+Answer with:
+- the leaders, each with publisher, listen score, episode count, how often it publishes and what sets it apart;
+- clusters of similar shows;
+- gaps: audiences, formats or subtopics that none of the leaders serve well.`,
+	},
+	{
+		Name:        "summarize_recent_episodes",
+		Description: "Summarize the latest episodes of a podcast.",
+		Args: []promptArg{
+			{Name: "podcast_id", Description: "Podcast id, e.g. from get_search or get_best_podcasts", Required: true},
+			{Name: "count", Description: "How many of the latest episodes to summarize, 1 to 30", Default: "5", Integer: true, Max: 30},
+		},
+		Template: `Summarize the latest {{.count}} episodes of podcast {{printf "%q" .podcast_id}}.
+
+1. Call get_podcasts_id with id={{printf "%q" .podcast_id}}. It returns the podcast and up to 10 of its episodes, newest first; call it again with next_episode_pub_date set to the response's next_episode_pub_date until you have {{.count}} episodes.
+2. If an episode's description is too short to summarize, call get_episodes_id with its id and show_transcript=1; the transcript is only available on some plans.
+
+Start with two sentences on what the show is about. Then, for each episode, newest first: title, publish date, length, a three-sentence summary and the guests, if any. End with the themes that recur across these episodes.`,
+	},
+	{
+		Name:        "build_playlist",
+		Description: "Build a listening playlist of episodes on a theme that fits a time budget.",
+		Args: []promptArg{
+			{Name: "theme", Description: "What the playlist is about, e.g. the history of the internet", Required: true},
+			{Name: "minutes", Description: "Total listening time in minutes, 1 to 1440", Default: "180", Integer: true, Max: 1440},
+			{Name: "language", Description: "Only include episodes in this language, e.g. English"},
+		},
+		Template: `Build a listening playlist about {{printf "%q" .theme}} that takes about {{.minutes}} minutes in total.
+
+1. Call get_search with q={{printf "%q" .theme}} and type="episode"{{with .language}}, language={{printf "%q" .}}{{end}}. Use len_min and len_max (in minutes) to find episodes that fit the time left.
+2. To avoid a playlist from just one or two shows, call get_podcasts_id_recommendations for the shows of the best episodes, and get_search again restricted to those shows (ocid).
+3. Call get_best_podcasts with a matching genre_id if the theme is broad and you need well-established shows.
+
+Answer with the playlist in listening order: episode title, podcast, length, one sentence on why it belongs, and its Listen Notes URL. Order it so that it starts with an introduction and builds up. Give the total length at the end; it must not exceed {{.minutes}} minutes by more than 10%.`,
+	},
+}
+
+// addPrompts registers the prompts on srv and returns how many there are.
+func addPrompts(srv *server.MCPServer) int {
+	for _, spec := range researchPrompts {
+		tmpl := template.Must(template.New(spec.Name).Option("missingkey=error").Parse(spec.Template))
+		srv.AddPrompt(newPrompt(spec), promptHandler(spec, tmpl))
+	}
+	return len(researchPrompts)
+}
+
+func newPrompt(spec promptSpec) mcp.Prompt {
+	opts := []mcp.PromptOption{mcp.WithPromptDescription(spec.Description)}
+	for _, arg := range spec.Args {
+		argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(arg.Description)}
+		if arg.Required {
+			argOpts = append(argOpts, mcp.RequiredArgument())
+		}
+		opts = append(opts, mcp.WithArgument(arg.Name, argOpts...))
+	}
+	return mcp.NewPrompt(spec.Name, opts...)
+}
+
+// promptHandler renders spec with the arguments of a prompts/get request.
+func promptHandler(spec promptSpec, tmpl *template.Template) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args, err := promptArgs(spec, request.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		var text strings.Builder
+		if err := tmpl.Execute(&text, args); err != nil {
+			return nil, err
+		}
+		return mcp.NewGetPromptResult(spec.Description, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text.String())),
+		}), nil
+	}
+}
+
+// promptArgs checks the arguments given for spec and fills in the defaults
+// of those left out.
+func promptArgs(spec promptSpec, given map[string]string) (map[string]string, error) {
+	args := make(map[string]string, len(spec.Args))
+	for _, arg := range spec.Args {
+		val := strings.TrimSpace(given[arg.Name])
+		if val == "" {
+			if arg.Required {
+				return nil, fmt.Errorf("%s is required", arg.Name)
+			}
+			val = arg.Default
+		}
+		if arg.Integer {
+			if n, err := strconv.Atoi(val); err != nil || n < 1 || n > arg.Max {
+				return nil, fmt.Errorf("%s must be an integer between 1 and %d", arg.Name, arg.Max)
+			}
+		}
+		args[arg.Name] = val
+	}
+	return args, nil
+}
@@ -1,37 +1,66 @@
 package main
 
 import (
+	"context"
+
 	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
 	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
-	tools_insights_api "github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/insights_api"
-	tools_search_api "github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/search_api"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/common"
 	tools_directory_api "github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/directory_api"
 	tools_podcaster_api "github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/podcaster_api"
-	tools_playlist_api "github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/playlist_api"
 )
 
+// overrides are hand-written tools that replace the generated tool for an
+// operation, keyed by operationId. Use them when a tool needs more than one
+// upstream call per invocation or validation the spec cannot express.
+var overrides = map[string]func(client *listenapi.Client) models.Tool{
+	"getPodcastsInBatch": tools_directory_api.CreateGetpodcastsinbatchTool,
+	"getEpisodesInBatch": tools_directory_api.CreateGetepisodesinbatchTool,
+	"submitPodcast":      tools_podcaster_api.CreateSubmitpodcastTool,
+}
+
+// pagedTools fetch several pages in one call when asked to with
+// common.PagesArgument, keyed by operationId.
+var pagedTools = map[string]func(ctx context.Context, client *listenapi.Client, args *common.Args, pages int) (any, error){
+	"search": func(ctx context.Context, client *listenapi.Client, args *common.Args, pages int) (any, error) {
+		return client.SearchPages(ctx, args.Query, pages)
+	},
+	"getBestPodcasts": func(ctx context.Context, client *listenapi.Client, args *common.Args, pages int) (any, error) {
+		return client.GetBestPodcastsPages(ctx, args.Query, pages)
+	},
+	"getPlaylistById": func(ctx context.Context, client *listenapi.Client, args *common.Args, pages int) (any, error) {
+		return client.GetPlaylistByIdPages(ctx, args.Path["id"], args.Query, pages)
+	},
+}
+
+// GetAll returns one tool per operation in openapi.yaml.
 func GetAll(cfg *config.APIConfig) []models.Tool {
-	return []models.Tool{
-		tools_insights_api.CreateGetpodcastsbydomainnameTool(cfg),
-		tools_search_api.CreateGetrelatedsearchesTool(cfg),
-		tools_directory_api.CreateGetcuratedpodcastbyidTool(cfg),
-		tools_directory_api.CreateGetcuratedpodcastsTool(cfg),
-		tools_directory_api.CreateJustlistenTool(cfg),
-		tools_podcaster_api.CreateDeletepodcastbyidTool(cfg),
-		tools_directory_api.CreateGetpodcastbyidTool(cfg),
-		tools_playlist_api.CreateGetplaylistsTool(cfg),
-		tools_search_api.CreateGettrendingsearchesTool(cfg),
-		tools_directory_api.CreateGetepisodebyidTool(cfg),
-		tools_playlist_api.CreateGetplaylistbyidTool(cfg),
-		tools_directory_api.CreateGetregionsTool(cfg),
-		tools_directory_api.CreateGetgenresTool(cfg),
-		tools_directory_api.CreateGetlanguagesTool(cfg),
-		tools_insights_api.CreateGetpodcastaudienceTool(cfg),
-		tools_directory_api.CreateGetpodcastrecommendationsTool(cfg),
-		tools_search_api.CreateTypeaheadTool(cfg),
-		tools_search_api.CreateSpellcheckTool(cfg),
-		tools_directory_api.CreateGetepisoderecommendationsTool(cfg),
-		tools_directory_api.CreateGetbestpodcastsTool(cfg),
-		tools_search_api.CreateSearchTool(cfg),
+	return toolsFor(cfg, listenapi.NewClient(cfg))
+}
+
+// toolsFor returns the tools of GetAll, calling the API with client.
+func toolsFor(cfg *config.APIConfig, client *listenapi.Client) []models.Tool {
+	tools := make([]models.Tool, 0, len(openapi.Operations))
+	for _, op := range openapi.Operations {
+		if create, ok := overrides[op.ID]; ok {
+			tools = append(tools, create(client))
+			continue
+		}
+		tool := common.OperationTool(client, op)
+		if fetch, ok := pagedTools[op.ID]; ok {
+			tool = common.WithPages(tool, op.Params, func(ctx context.Context, args *common.Args, pages int) (any, error) {
+				return fetch(ctx, client, args, pages)
+			})
+		}
+		tools = append(tools, tool)
+	}
+
+	if cfg.AllowAPIKeyArgument {
+		for i, tool := range tools {
+			tools[i] = common.WithAPIKeyArgument(tool)
+		}
 	}
+	return tools
 }
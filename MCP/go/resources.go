@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// resourceScheme prefixes the URI of every resource the server exposes.
+const resourceScheme = "listennotes://"
+
+// resourceSpec exposes the response of an operation as an MCP resource. A
+// URI with an {id} is a template whose id becomes the operation's id path
+// parameter.
+type resourceSpec struct {
+	URI         string
+	Name        string
+	Description string
+	Operation   string // operationId
+}
+
+// resourceTemplates let a client attach a single record to context without
+// asking the model to call a tool.
+var resourceTemplates = []resourceSpec{
+	{resourceScheme + "podcast/{id}", "Podcast", "A podcast's meta data and its latest episodes, as returned by get_podcasts_id.", "getPodcastById"},
+	{resourceScheme + "episode/{id}", "Episode", "An episode's meta data, as returned by get_episodes_id.", "getEpisodeById"},
+	{resourceScheme + "playlist/{id}", "Playlist", "A playlist's info and items, as returned by get_playlists_id.", "getPlaylistById"},
+	{resourceScheme + "curated/{id}", "Curated list", "A curated list of podcasts, as returned by get_curated_podcasts_id.", "getCuratedPodcastById"},
+}
+
+// staticResources are the reference lists other tools take ids and codes
+// from.
+var staticResources = []resourceSpec{
+	{resourceScheme + "genres", "Genres", "Every podcast genre, with its id and parent id.", "getGenres"},
+	{resourceScheme + "languages", "Languages", "Every language podcasts can be filtered by.", "getLanguages"},
+	{resourceScheme + "regions", "Regions", "Every region best podcasts can be listed for, by code.", "getRegions"},
+}
+
+// addResources registers the resource templates and static resources on
+// srv and returns how many there are. They are read with client, so they
+// share its cache and rate limit with the tools.
+func addResources(srv *server.MCPServer, client *listenapi.Client) int {
+	for _, spec := range resourceTemplates {
+		op := mustLookup(spec.Operation)
+		srv.AddResourceTemplate(
+			newResourceTemplate(spec),
+			resourceHandler(client, op),
+		)
+	}
+	for _, spec := range staticResources {
+		op := mustLookup(spec.Operation)
+		srv.AddResource(
+			newResource(spec),
+			resourceHandler(client, op),
+		)
+	}
+	return len(resourceTemplates) + len(staticResources)
+}
+
+func newResourceTemplate(spec resourceSpec) mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(spec.URI, spec.Name,
+		mcp.WithTemplateDescription(spec.Description),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+}
+
+func newResource(spec resourceSpec) mcp.Resource {
+	return mcp.NewResource(spec.URI, spec.Name,
+		mcp.WithResourceDescription(spec.Description),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+func mustLookup(id string) openapi.Operation {
+	op, ok := openapi.Lookup(id)
+	if !ok {
+		panic("resources: no operation " + id)
+	}
+	return op
+}
+
+// resourceHandler reads a resource by calling op, with the id of a template
+// URI bound like a tool argument. The contents are the response as the API
+// sent it.
+func resourceHandler(client *listenapi.Client, op openapi.Operation) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		args := map[string]any{}
+		if id, ok := templateVar(request.Params.Arguments, "id"); ok {
+			args["id"] = id
+		}
+		bound, err := common.Bind(args, op.Params)
+		if err != nil {
+			return nil, err
+		}
+		result, err := op.Call(ctx, client, bound.Path, bound.Query, bound.Form)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", request.Params.URI, err)
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", request.Params.URI, err)
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		}, nil
+	}
+}
+
+// templateVar returns the unescaped value of a template variable, which the
+// template matcher hands over as a string or a list of them.
+func templateVar(vars map[string]any, name string) (string, bool) {
+	var s string
+	switch v := vars[name].(type) {
+	case string:
+		s = v
+	case []string:
+		if len(v) != 1 {
+			return "", false
+		}
+		s = v[0]
+	default:
+		return "", false
+	}
+	if unescaped, err := url.PathUnescape(s); err == nil {
+		s = unescaped
+	}
+	return s, true
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sessionStore keeps the state of every live HTTP session: the API
+// configuration captured from the headers of its initialize request, and
+// when it was last used. It is the session id manager of the Streamable HTTP
+// transport, so unknown and expired ids are rejected before a message is
+// handled. A separate store tracks the sessions of the legacy SSE transport;
+// see registerSSE.
+type sessionStore struct {
+	base  *config.APIConfig // server configuration that headers override
+	ttl   time.Duration
+	onEnd func(sessionID string) // called when a session is terminated or expires, if set
+
+	mu       sync.Mutex
+	sessions map[string]*sessionState
+}
+
+type sessionState struct {
+	cfg      *config.APIConfig // nil until the initialize request is handled
+	lastSeen time.Time
+	streams  int // open GET streams, which keep the session from expiring
+}
+
+func newSessionStore(base *config.APIConfig) *sessionStore {
+	ttl := base.SessionTTL
+	if ttl <= 0 {
+		ttl = config.DefaultSessionTTL
+	}
+	return &sessionStore{
+		base:     base,
+		ttl:      ttl,
+		sessions: make(map[string]*sessionState),
+	}
+}
+
+// Generate starts a new session. It is called for every initialize request.
+func (s *sessionStore) Generate() string {
+	id := "mcp-session-" + rand.Text()
+	s.mu.Lock()
+	s.sessions[id] = &sessionState{lastSeen: time.Now()}
+	s.mu.Unlock()
+	return id
+}
+
+// Validate accepts live sessions and refreshes their idle timer. Ids that
+// are unknown, expired or deleted are reported as terminated so that the
+// client answers the 404 by initializing a new session.
+func (s *sessionStore) Validate(sessionID string) (isTerminated bool, err error) {
+	if sessionID == "" {
+		return false, errors.New("missing session id")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.sessions[sessionID]
+	if !ok {
+		return true, nil
+	}
+	state.lastSeen = time.Now()
+	return false, nil
+}
+
+// Terminate ends a session on the client's DELETE request.
+func (s *sessionStore) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	s.mu.Lock()
+	delete(s.sessions, sessionID)
+	s.mu.Unlock()
+	if s.onEnd != nil {
+		s.onEnd(sessionID)
+	}
+	return false, nil
+}
+
+// has reports whether sessionID belongs to a live session.
+func (s *sessionStore) has(sessionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.sessions[sessionID]
+	return ok
+}
+
+// listen keeps sessionID from expiring while one of its GET streams is
+// open: a client that only waits for notifications, such as the updates of
+// its subscriptions, sends no requests that would refresh its idle timer.
+// The idle timer starts over when the returned func closes the stream.
+func (s *sessionStore) listen(sessionID string) (closed func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.sessions[sessionID]
+	if !ok {
+		return func() {}
+	}
+	state.streams++
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		state.streams--
+		state.lastSeen = time.Now()
+	}
+}
+
+// config returns the API configuration of sessionID, or nil if it is not a
+// live, initialized session.
+func (s *sessionStore) config(sessionID string) *config.APIConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state, ok := s.sessions[sessionID]; ok {
+		return state.cfg
+	}
+	return nil
+}
+
+// expireIdle removes sessions idle for longer than the TTL every interval
+// until ctx is done. Sessions with an open GET stream are not idle.
+func (s *sessionStore) expireIdle(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = config.DefaultSessionCleanupInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			var expired []string
+			s.mu.Lock()
+			for id, state := range s.sessions {
+				if state.streams == 0 && now.Sub(state.lastSeen) > s.ttl {
+					delete(s.sessions, id)
+					expired = append(expired, id)
+				}
+			}
+			s.mu.Unlock()
+			if s.onEnd != nil {
+				for _, id := range expired {
+					s.onEnd(id)
+				}
+			}
+		}
+	}
+}
+
+// contextFunc hands the session's API configuration to the tool handlers.
+// The configuration is captured from the headers of the first request of a
+// session, its initialize request, and reused for the rest of the session.
+func (s *sessionStore) contextFunc(ctx context.Context, r *http.Request) context.Context {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return ctx
+	}
+	sessionID := session.SessionID()
+
+	s.mu.Lock()
+	state, ok := s.sessions[sessionID]
+	if ok && state.cfg == nil {
+		state.cfg = configFromHeaders(s.base, r.Header)
+		log.Printf("Session started - BaseURL: %s", state.cfg.BaseURL)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		// Not a session this store issued, e.g. a GET stream opened without
+		// a session id; it only ever receives notifications.
+		return listenapi.WithConfig(ctx, configFromHeaders(s.base, r.Header))
+	}
+	return listenapi.WithConfig(ctx, state.cfg)
+}
+
+// belongsTo reports whether a request carrying headers h may use sessionID.
+// A session is bound to the tenant that initialized it: any connection
+// header on a later request must match what was captured, so that a leaked
+// or guessed session id cannot borrow another tenant's credentials and a
+// client cannot switch tenants mid-session. Unknown sessions are left to the
+// transport to reject.
+func (s *sessionStore) belongsTo(sessionID string, h http.Header) bool {
+	s.mu.Lock()
+	state, ok := s.sessions[sessionID]
+	var cfg *config.APIConfig
+	if ok {
+		cfg = state.cfg
+	}
+	s.mu.Unlock()
+	if cfg == nil {
+		return true
+	}
+	return sameTenant(cfg, configFromHeaders(cfg, h))
+}
+
+// sameTenant reports whether a and b reach the same API with the same
+// credentials.
+func sameTenant(a, b *config.APIConfig) bool {
+	equal := func(x, y string) bool {
+		return subtle.ConstantTimeCompare([]byte(x), []byte(y)) == 1
+	}
+	return a.BaseURL == b.BaseURL &&
+		equal(a.APIKey, b.APIKey) &&
+		equal(a.BearerToken, b.BearerToken) &&
+		equal(a.BasicAuth, b.BasicAuth)
+}
+
+// tenantGuard rejects requests to a session from a different tenant.
+// sessionID extracts the session id from the request.
+func (s *sessionStore) tenantGuard(sessionID func(r *http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := sessionID(r); id != "" && !s.belongsTo(id, r.Header) {
+			http.Error(w, "Session belongs to a different tenant", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sseHeadersKey carries the headers of a GET /sse request to the session
+// hooks, which only see the request context.
+type sseHeadersKey struct{}
+
+// sseHandler wraps the SSE endpoint of the legacy SSE transport. A session
+// lives as long as its GET /sse stream, and its configuration comes from the
+// headers of that request.
+func (s *sessionStore) sseHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("API_BASE_URL") == "" && s.base.BaseURL == "" {
+			http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
+			return
+		}
+		if err := s.checkBaseURL(r.Header); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		ctx := context.WithValue(r.Context(), sseHeadersKey{}, r.Header)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// registerSSE makes the store track the sessions of the legacy SSE
+// transport, which issues its own session ids.
+func (s *sessionStore) registerSSE(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		h, ok := ctx.Value(sseHeadersKey{}).(http.Header)
+		if !ok {
+			return
+		}
+		cfg := configFromHeaders(s.base, h)
+		s.mu.Lock()
+		s.sessions[session.SessionID()] = &sessionState{cfg: cfg, lastSeen: time.Now()}
+		s.mu.Unlock()
+		log.Printf("SSE session started - BaseURL: %s", cfg.BaseURL)
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		if _, ok := ctx.Value(sseHeadersKey{}).(http.Header); ok {
+			s.Terminate(session.SessionID())
+		}
+	})
+}
+
+// errBaseURLOverride refuses a session that asks a server with credentials
+// of its own to call another base URL.
+var errBaseURLOverride = errors.New("API_BASE_URL cannot be changed on this server, which sends its own credentials")
+
+// checkBaseURL refuses headers that would send the server's credentials to
+// a base URL the client chose. Credentials the client does not send fall
+// back to the server's, so a client may only pick the base URL when the
+// server has no credentials at all.
+func (s *sessionStore) checkBaseURL(h http.Header) error {
+	url := h.Get("API_BASE_URL")
+	if url == "" || url == s.base.BaseURL {
+		return nil
+	}
+	if s.base.APIKey != "" || s.base.BearerToken != "" || s.base.BasicAuth != "" {
+		return errBaseURLOverride
+	}
+	return nil
+}
+
+// configFromHeaders returns a copy of base with the connection settings the
+// client sent as headers.
+func configFromHeaders(base *config.APIConfig, h http.Header) *config.APIConfig {
+	cfg := *base
+	overrideFromHeader(&cfg.BaseURL, h, "API_BASE_URL")
+	overrideFromHeader(&cfg.BearerToken, h, "BEARER_TOKEN")
+	overrideFromHeader(&cfg.APIKey, h, "API_KEY")
+	overrideFromHeader(&cfg.APIKey, h, "X-ListenAPI-Key")
+	overrideFromHeader(&cfg.BasicAuth, h, "BASIC_AUTH")
+	return &cfg
+}
+
+// overrideFromHeader replaces *field with the named header when the client
+// sent one.
+func overrideFromHeader(field *string, h http.Header, name string) {
+	if val := h.Get(name); val != "" {
+		*field = val
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// serveHTTP serves cfg in mode, HTTP or SSE, on a test server.
+func serveHTTP(t *testing.T, cfg *config.APIConfig, mode string) *httptest.Server {
+	t.Helper()
+	httpServer := &http.Server{}
+	handler, _, stop := newHTTPHandler(cfg, mode, httpServer)
+	srv := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		srv.CloseClientConnections()
+		srv.Close()
+		stop()
+	})
+	return srv
+}
+
+// startSession initializes a session on srv, over the legacy SSE transport
+// with sse, sending headers with every request.
+func startSession(t *testing.T, srv *httptest.Server, sse bool, headers map[string]string) (*client.Client, error) {
+	t.Helper()
+	var c *client.Client
+	var err error
+	if sse {
+		c, err = client.NewSSEMCPClient(srv.URL+"/sse", transport.WithHeaders(headers))
+	} else {
+		c, err = client.NewStreamableHttpClient(srv.URL+"/mcp", transport.WithHTTPHeaders(headers))
+	}
+	if err != nil {
+		t.Fatalf("creating the client: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	if err := c.Start(context.Background()); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req := mcp.InitializeRequest{}
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := c.Initialize(ctx, req); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// sessionID returns the id of the session c started.
+func sessionID(c *client.Client) string {
+	switch tr := c.GetTransport().(type) {
+	case *transport.StreamableHTTP:
+		return tr.GetSessionId()
+	case *transport.SSE:
+		return tr.GetEndpoint().Query().Get("sessionId")
+	}
+	return ""
+}
+
+// post sends a ping on session id of srv, on the endpoint of the
+// Streamable HTTP transport or, with sse, of the legacy SSE transport, and
+// returns the status code.
+func post(t *testing.T, srv *httptest.Server, sse bool, id string, headers map[string]string) int {
+	t.Helper()
+	endpoint := srv.URL + "/mcp"
+	if sse {
+		endpoint = srv.URL + "/message?sessionId=" + url.QueryEscape(id)
+	}
+	req, _ := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(`{"jsonrpc":"2.0","id":99,"method":"ping"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if !sse {
+		req.Header.Set(server.HeaderKeySessionID, id)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", endpoint, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestTenantIsolation(t *testing.T) {
+	for _, mode := range []string{"HTTP", "SSE"} {
+		t.Run(mode, func(t *testing.T) {
+			sse := mode == "SSE"
+			// A shared deployment: every tenant brings its base URL and key
+			srv := serveHTTP(t, &config.APIConfig{Cache: config.CacheOff}, mode)
+			tenant := func(baseURL, key string) map[string]string {
+				return map[string]string{"API_BASE_URL": baseURL, "X-ListenAPI-Key": key}
+			}
+
+			t.Run("session bound to its tenant", func(t *testing.T) {
+				upstream, other := newFakeUpstream(t), newFakeUpstream(t)
+				c, err := startSession(t, srv, sse, tenant(upstream.URL, "key-a"))
+				if err != nil {
+					t.Fatalf("starting the session: %v", err)
+				}
+				id := sessionID(c)
+
+				cases := []struct {
+					name    string
+					headers map[string]string
+					want    int
+				}{
+					{"same tenant", tenant(upstream.URL, "key-a"), http.StatusOK},
+					{"no connection headers", map[string]string{}, http.StatusOK},
+					{"other API key", map[string]string{"X-ListenAPI-Key": "key-b"}, http.StatusForbidden},
+					{"other API_KEY", map[string]string{"API_KEY": "key-b"}, http.StatusForbidden},
+					{"other base URL", map[string]string{"API_BASE_URL": other.URL}, http.StatusForbidden},
+				}
+				for _, tc := range cases {
+					want := tc.want
+					if sse && want == http.StatusOK {
+						// Replies travel on the event stream
+						want = http.StatusAccepted
+					}
+					if got := post(t, srv, sse, id, tc.headers); got != want {
+						t.Errorf("%s: status %d, want %d", tc.name, got, want)
+					}
+				}
+				if requests := other.take(); len(requests) != 0 {
+					t.Errorf("the other base URL received %d requests, want none", len(requests))
+				}
+			})
+
+			t.Run("each session sends its own key", func(t *testing.T) {
+				upstream := newFakeUpstream(t)
+				a, err := startSession(t, srv, sse, tenant(upstream.URL, "key-a"))
+				if err != nil {
+					t.Fatalf("starting session a: %v", err)
+				}
+				b, err := startSession(t, srv, sse, tenant(upstream.URL, "key-b"))
+				if err != nil {
+					t.Fatalf("starting session b: %v", err)
+				}
+				for _, call := range []struct {
+					c   *client.Client
+					key string
+				}{{a, "key-a"}, {b, "key-b"}, {a, "key-a"}, {b, "key-b"}} {
+					if res := callTool(t, call.c, "get_genres", map[string]any{}); res.IsError {
+						t.Fatalf("get_genres failed: %s", resultText(res))
+					}
+					requests := upstream.take()
+					if len(requests) != 1 || requests[0].Header.Get("X-ListenAPI-Key") != call.key {
+						t.Errorf("upstream requests %v, want one with %s", requests, call.key)
+					}
+				}
+			})
+
+			t.Run("cancellation scoped to the session", func(t *testing.T) {
+				upstream := newFakeUpstream(t)
+				started, aborted := upstream.block()
+				a, err := startSession(t, srv, sse, tenant(upstream.URL, "key-a"))
+				if err != nil {
+					t.Fatalf("starting session a: %v", err)
+				}
+				b, err := startSession(t, srv, sse, tenant(upstream.URL, "key-a"))
+				if err != nil {
+					t.Fatalf("starting session b: %v", err)
+				}
+
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					ctx, stop := context.WithTimeout(context.Background(), 15*time.Second)
+					defer stop()
+					req := mcp.CallToolRequest{}
+					req.Params.Name = "get_genres"
+					req.Params.Arguments = map[string]any{}
+					a.CallTool(ctx, req)
+				}()
+				waitFor(t, started, "the upstream request starts")
+
+				// Session b's first call would have the same id
+				cancel(t, b, firstCallID)
+				select {
+				case <-aborted:
+					t.Fatal("another session cancelled the call")
+				case <-time.After(300 * time.Millisecond):
+				}
+				cancel(t, a, firstCallID)
+				waitFor(t, aborted, "the upstream request is aborted")
+				waitFor(t, done, "the call returns")
+			})
+
+			t.Run("server credentials stay with the server's base URL", func(t *testing.T) {
+				upstream := newFakeUpstream(t)
+				attacker := newFakeUpstream(t)
+				cfg := testConfig(upstream.URL)
+				cfg.APIKey, cfg.BearerToken = "SERVER-SECRET", "GATEWAY-TOKEN"
+				srv := serveHTTP(t, cfg, mode)
+
+				for _, headers := range []map[string]string{
+					{"API_BASE_URL": attacker.URL},
+					{"API_BASE_URL": attacker.URL, "X-ListenAPI-Key": "client-key"},
+				} {
+					if _, err := startSession(t, srv, sse, headers); err == nil {
+						t.Errorf("session with headers %v started, want it refused", headers)
+					}
+				}
+				if requests := attacker.take(); len(requests) != 0 {
+					t.Errorf("the client's base URL received %d requests, want none", len(requests))
+				}
+
+				// The server's own base URL may be named
+				c, err := startSession(t, srv, sse, map[string]string{"API_BASE_URL": upstream.URL})
+				if err != nil {
+					t.Fatalf("session with the server's base URL: %v", err)
+				}
+				if res := callTool(t, c, "get_genres", map[string]any{}); res.IsError {
+					t.Fatalf("get_genres failed: %s", resultText(res))
+				}
+				requests := upstream.take()
+				if len(requests) != 1 || requests[0].Header.Get("X-ListenAPI-Key") != "SERVER-SECRET" {
+					t.Errorf("upstream requests %v, want one with the server's key", requests)
+				}
+			})
+		})
+	}
+}
+
+func TestListeningSessionOutlivesTTL(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	upstream.respond(http.StatusOK, `{"id":"p1","latest_pub_date_ms":1,"latest_episode_id":"e1"}`)
+	cfg := testConfig(upstream.URL)
+	cfg.SessionTTL = 100 * time.Millisecond
+	cfg.SessionCleanupInterval = 10 * time.Millisecond
+	cfg.SubscriptionPollInterval = 20 * time.Millisecond
+	c := connectHTTP(t, cfg)
+
+	updates := make(chan struct{}, 16)
+	c.OnNotification(func(n mcp.JSONRPCNotification) {
+		if n.Method == mcp.MethodNotificationResourceUpdated {
+			updates <- struct{}{}
+		}
+	})
+	ctx, stop := context.WithTimeout(context.Background(), 10*time.Second)
+	defer stop()
+	req := mcp.SubscribeRequest{}
+	req.Params.URI = "listennotes://podcast/p1"
+	if err := c.Subscribe(ctx, req); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	// The client only listens on its GET stream for several TTLs
+	time.Sleep(5 * cfg.SessionTTL)
+	upstream.respond(http.StatusOK, `{"id":"p1","latest_pub_date_ms":2,"latest_episode_id":"e2"}`)
+	waitFor(t, updates, "the subscription reports the new episode")
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// The methods of resource subscriptions, which mcp-go does not define.
+const (
+	methodResourcesSubscribe   mcp.MCPMethod = "resources/subscribe"
+	methodResourcesUnsubscribe mcp.MCPMethod = "resources/unsubscribe"
+)
+
+// podcastURIPrefix starts the URI of every podcast resource; the rest is
+// the podcast id.
+const podcastURIPrefix = resourceScheme + "podcast/"
+
+// podcastWatcher lets clients subscribe to podcast resources. While anyone
+// is subscribed, it polls the podcasts in the background and sends
+// notifications/resources/updated when one publishes a new episode, i.e.
+// when its latest_pub_date_ms or latest_episode_id changes.
+//
+// Each podcast is polled once per tenant, however many sessions of the
+// tenant subscribed to it, and with the tenant's credentials. Polls are
+// spaced by the configured interval, stretched when the tenant's quota runs
+// low; see pollInterval.
+type podcastWatcher struct {
+	srv      *server.MCPServer
+	client   *listenapi.Client
+	base     *config.APIConfig // for sessions that carry no configuration
+	interval time.Duration
+
+	mu      sync.Mutex
+	watches []*watch
+	running bool
+	wake    chan struct{} // tells the poller that watches changed
+}
+
+// watch is one podcast polled for one tenant.
+type watch struct {
+	id       string
+	cfg      *config.APIConfig
+	sessions map[string]string // URI each subscribed session used, by session id
+	latest   latestEpisode
+	next     time.Time // when to poll next
+}
+
+// latestEpisode is what identifies the newest episode of a podcast.
+type latestEpisode struct {
+	PubDateMs int    `json:"latest_pub_date_ms"`
+	EpisodeID string `json:"latest_episode_id"`
+}
+
+func newPodcastWatcher(srv *server.MCPServer, client *listenapi.Client, cfg *config.APIConfig) *podcastWatcher {
+	interval := cfg.SubscriptionPollInterval
+	if interval <= 0 {
+		interval = config.DefaultSubscriptionPollInterval
+	}
+	return &podcastWatcher{
+		srv:      srv,
+		client:   client,
+		base:     cfg,
+		interval: interval,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// register adds the resources/subscribe and resources/unsubscribe methods
+// to extra.
+func (w *podcastWatcher) register(extra *extraMethods) {
+	extra.add(methodResourcesSubscribe, w.subscribe)
+	extra.add(methodResourcesUnsubscribe, w.unsubscribe)
+}
+
+// podcastID returns the id of the podcast a resource URI names.
+func podcastID(uri string) (string, error) {
+	rest, ok := strings.CutPrefix(uri, podcastURIPrefix)
+	if !ok || rest == "" || strings.Contains(rest, "/") {
+		return "", invalidParams("only podcasts can be subscribed to, as %s{id}; got %q", podcastURIPrefix, uri)
+	}
+	id, err := url.PathUnescape(rest)
+	if err != nil || id == "" {
+		return "", invalidParams("invalid podcast id in %q", uri)
+	}
+	return id, nil
+}
+
+// subscribe is the resources/subscribe handler. The first subscription to
+// a podcast fetches it, so that a podcast that does not exist is reported
+// right away, and so that later polls have something to compare with.
+func (w *podcastWatcher) subscribe(ctx context.Context, sessionID string, params json.RawMessage) (any, error) {
+	var p mcp.SubscribeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams("invalid params: %v", err)
+	}
+	id, err := podcastID(p.URI)
+	if err != nil {
+		return nil, err
+	}
+	cfg := listenapi.ConfigFrom(ctx)
+	if cfg == nil {
+		cfg = w.base
+	}
+
+	w.mu.Lock()
+	if wt := w.find(id, cfg); wt != nil {
+		wt.sessions[sessionID] = p.URI
+		w.mu.Unlock()
+		return mcp.EmptyResult{}, nil
+	}
+	w.mu.Unlock()
+
+	latest, err := w.fetch(ctx, id, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wt := w.find(id, cfg)
+	if wt == nil {
+		// Not added by a concurrent subscribe while the podcast was fetched
+		wt = &watch{id: id, cfg: cfg, sessions: map[string]string{}, latest: latest}
+		w.watches = append(w.watches, wt)
+		wt.next = time.Now().Add(w.nextInterval(cfg))
+	}
+	wt.sessions[sessionID] = p.URI
+	w.start()
+	return mcp.EmptyResult{}, nil
+}
+
+// unsubscribe is the resources/unsubscribe handler.
+func (w *podcastWatcher) unsubscribe(ctx context.Context, sessionID string, params json.RawMessage) (any, error) {
+	var p mcp.UnsubscribeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams("invalid params: %v", err)
+	}
+	id, err := podcastID(p.URI)
+	if err != nil {
+		return nil, err
+	}
+	cfg := listenapi.ConfigFrom(ctx)
+	if cfg == nil {
+		cfg = w.base
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if wt := w.find(id, cfg); wt != nil {
+		delete(wt.sessions, sessionID)
+		w.prune()
+	}
+	return mcp.EmptyResult{}, nil
+}
+
+// endSession drops every subscription of a session that has ended.
+func (w *podcastWatcher) endSession(sessionID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, wt := range w.watches {
+		delete(wt.sessions, sessionID)
+	}
+	w.prune()
+}
+
+// find returns the watch of podcast id for the tenant of cfg. w.mu must be
+// held.
+func (w *podcastWatcher) find(id string, cfg *config.APIConfig) *watch {
+	for _, wt := range w.watches {
+		if wt.id == id && sameTenant(wt.cfg, cfg) {
+			return wt
+		}
+	}
+	return nil
+}
+
+// prune removes the watches nobody is subscribed to any more. w.mu must be
+// held.
+func (w *podcastWatcher) prune() {
+	kept := w.watches[:0]
+	for _, wt := range w.watches {
+		if len(wt.sessions) > 0 {
+			kept = append(kept, wt)
+		}
+	}
+	clear(w.watches[len(kept):])
+	w.watches = kept
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// start runs the poller unless it is running already. w.mu must be held.
+func (w *podcastWatcher) start() {
+	if w.running {
+		return
+	}
+	w.running = true
+	go w.run()
+}
+
+// run polls every watch when it is due, until there are none left.
+func (w *podcastWatcher) run() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		w.mu.Lock()
+		if len(w.watches) == 0 {
+			w.running = false
+			w.mu.Unlock()
+			return
+		}
+		now := time.Now()
+		var due []*watch
+		next := now.Add(w.interval)
+		for _, wt := range w.watches {
+			if !wt.next.After(now) {
+				due = append(due, wt)
+			} else if wt.next.Before(next) {
+				next = wt.next
+			}
+		}
+		w.mu.Unlock()
+
+		for _, wt := range due {
+			w.poll(wt)
+		}
+		if len(due) > 0 {
+			continue
+		}
+
+		timer.Reset(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-w.wake:
+		}
+	}
+}
+
+// poll fetches the podcast of wt and notifies the subscribed sessions if it
+// has a new episode.
+func (w *podcastWatcher) poll(wt *watch) {
+	latest, err := w.fetch(context.Background(), wt.id, wt.cfg)
+
+	w.mu.Lock()
+	wt.next = time.Now().Add(w.nextInterval(wt.cfg))
+	if err != nil {
+		w.mu.Unlock()
+		var quotaErr *listenapi.QuotaError
+		if !errors.As(err, &quotaErr) {
+			log.Printf("Polling podcast %s failed: %v", wt.id, err)
+		}
+		return
+	}
+	changed := latest != wt.latest
+	wt.latest = latest
+	uris := make(map[string]string, len(wt.sessions))
+	for sessionID, uri := range wt.sessions {
+		uris[sessionID] = uri
+	}
+	w.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	for sessionID, uri := range uris {
+		// A session that is not listening right now, such as a Streamable
+		// HTTP session without an open GET stream, misses the update
+		w.srv.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+	}
+}
+
+// fetch returns the latest episode of podcast id, bypassing the response
+// cache.
+func (w *podcastWatcher) fetch(ctx context.Context, id string, cfg *config.APIConfig) (latestEpisode, error) {
+	ctx = listenapi.WithRefresh(listenapi.WithConfig(ctx, cfg))
+	resp, err := w.client.GetPodcastById(ctx, id, nil)
+	if err != nil {
+		return latestEpisode{}, err
+	}
+	var latest latestEpisode
+	if err := json.Unmarshal(resp.Raw, &latest); err != nil {
+		return latestEpisode{}, err
+	}
+	return latest, nil
+}
+
+// nextInterval returns how long to wait before the next poll of a podcast
+// watched for the tenant of cfg. w.mu must be held.
+func (w *podcastWatcher) nextInterval(cfg *config.APIConfig) time.Duration {
+	n := 0
+	for _, wt := range w.watches {
+		if sameTenant(wt.cfg, cfg) {
+			n++
+		}
+	}
+	usage := w.client.Usage(listenapi.WithConfig(context.Background(), cfg))
+	return pollInterval(w.interval, max(n, 1), usage, time.Now())
+}
+
+// pollInterval returns how often each of n podcasts may be polled for a
+// tenant with usage: every base, unless that would spend more than half of
+// the requests left in the billing cycle before it ends, in which case the
+// polls are spread out over the rest of the cycle. With no requests left,
+// polling waits for the next cycle. Without a known quota and billing date,
+// it is base.
+func pollInterval(base time.Duration, n int, usage listenapi.Usage, now time.Time) time.Duration {
+	quota := usage.SoftQuota
+	if quota == 0 {
+		quota = usage.FreeQuota
+	}
+	if quota == 0 || usage.NextBillingDate.IsZero() {
+		return base
+	}
+	untilBilling := usage.NextBillingDate.Sub(now)
+	if untilBilling <= 0 {
+		return base
+	}
+	budget := (quota - usage.Used) / 2
+	if budget < n {
+		return max(base, untilBilling)
+	}
+	rounds := budget / n
+	return max(base, untilBilling/time.Duration(rounds))
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+)
+
+func TestPollInterval(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	billing := now.Add(100 * time.Hour)
+	base := 15 * time.Minute
+
+	cases := []struct {
+		name  string
+		n     int
+		usage listenapi.Usage
+		want  time.Duration
+	}{
+		{"no quota reported", 10, listenapi.Usage{Used: 500}, base},
+		{"no billing date", 10, listenapi.Usage{Used: 500, FreeQuota: 1000}, base},
+		{"plenty left", 1, listenapi.Usage{Used: 0, FreeQuota: 100000, NextBillingDate: billing}, base},
+		// 1000 left, 500 to spend on 10 podcasts: 50 rounds in 100 hours
+		{"free quota running low", 10, listenapi.Usage{Used: 9000, FreeQuota: 10000, NextBillingDate: billing}, 2 * time.Hour},
+		{"soft quota wins", 10, listenapi.Usage{Used: 900, FreeQuota: 10000, SoftQuota: 1900, NextBillingDate: billing}, 2 * time.Hour},
+		{"not enough for one round", 10, listenapi.Usage{Used: 990, FreeQuota: 1000, NextBillingDate: billing}, 100 * time.Hour},
+		{"used up", 1, listenapi.Usage{Used: 1200, FreeQuota: 1000, NextBillingDate: billing}, 100 * time.Hour},
+		{"billing date passed", 1, listenapi.Usage{Used: 1200, FreeQuota: 1000, NextBillingDate: now.Add(-time.Hour)}, base},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := pollInterval(base, tc.n, tc.usage, now); got != tc.want {
+				t.Errorf("pollInterval = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
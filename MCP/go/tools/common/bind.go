@@ -0,0 +1,303 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+)
+
+// ParamError is a tool argument that failed validation.
+type ParamError struct {
+	Parameter string `json:"parameter"`
+	Message   string `json:"message"`
+}
+
+// ValidationError lists every invalid argument of a tool call. Tools return
+// it before making any upstream request; ErrorResult renders it with the
+// errors as structured content.
+type ValidationError struct {
+	Errors []ParamError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, pe := range e.Errors {
+		msgs[i] = pe.Parameter + " " + pe.Message
+	}
+	return "Invalid arguments: " + strings.Join(msgs, "; ")
+}
+
+// Add records that parameter is invalid; message reads after its name, e.g.
+// "is required".
+func (e *ValidationError) Add(parameter, message string) {
+	e.Errors = append(e.Errors, ParamError{Parameter: parameter, Message: message})
+}
+
+// Err returns e, or nil when no errors were added.
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Args are tool arguments bound to the parameters of an operation and
+// rendered as request values. Path values are unescaped; the listenapi
+// client escapes them.
+type Args struct {
+	Path  map[string]string
+	Query url.Values
+	Form  url.Values
+}
+
+// paramBounds are limits the spec states only in parameter descriptions.
+// max is +Inf when there is no upper limit.
+var paramBounds = map[string]struct{ min, max float64 }{
+	"page_size":         {1, 10},
+	"pages":             {1, listenapi.MaxPages},
+	"page":              {1, math.Inf(1)},
+	"offset":            {0, math.Inf(1)},
+	"len_min":           {0, math.Inf(1)},
+	"len_max":           {0, math.Inf(1)},
+	"episode_count_min": {0, math.Inf(1)},
+	"episode_count_max": {0, math.Inf(1)},
+	"update_freq_min":   {0, math.Inf(1)},
+	"update_freq_max":   {0, math.Inf(1)},
+}
+
+// paramOrder are pairs of arguments where low may not exceed high, or must
+// be below it when strict.
+var paramOrder = []struct {
+	low, high string
+	strict    bool
+}{
+	{"published_after", "published_before", true},
+	{"len_min", "len_max", false},
+	{"episode_count_min", "episode_count_max", false},
+	{"update_freq_min", "update_freq_max", false},
+}
+
+// timestampParams are millisecond timestamps, which may also be given as an
+// RFC 3339 time or a date such as 2024-01-31.
+var timestampParams = map[string]bool{
+	"published_before":      true,
+	"published_after":       true,
+	"next_episode_pub_date": true,
+	"last_timestamp_ms":     true,
+}
+
+// Bind checks args against params and renders them for the request:
+// integers without an exponent or fraction, lists of strings joined with
+// commas. It reports every missing, mistyped, out-of-range or inconsistent
+// argument at once as a *ValidationError. Arguments that are not
+// parameters are ignored.
+func Bind(args map[string]any, params []openapi.Param) (*Args, error) {
+	bound := &Args{Path: map[string]string{}, Query: url.Values{}, Form: url.Values{}}
+	verr := &ValidationError{}
+	numbers := map[string]float64{}
+	for _, p := range params {
+		val, ok := args[p.Name]
+		if !ok || val == nil {
+			if p.Required {
+				verr.Add(p.Name, "is required")
+			}
+			continue
+		}
+		s, n, problem := bindValue(p, val)
+		switch {
+		case problem != "":
+			verr.Add(p.Name, problem)
+			continue
+		case s == "":
+			// An empty optional argument is left out of the request
+			if p.Required {
+				verr.Add(p.Name, "must not be empty")
+			}
+			continue
+		case len(p.Enum) > 0 && !inEnum(s, p.Enum):
+			verr.Add(p.Name, "must be one of "+joinEnum(p.Enum))
+			continue
+		}
+		if p.Type == "integer" || p.Type == "number" {
+			if b, ok := paramBounds[p.Name]; ok && (n < b.min || n > b.max) {
+				if math.IsInf(b.max, 1) {
+					verr.Add(p.Name, fmt.Sprintf("must be at least %g", b.min))
+				} else {
+					verr.Add(p.Name, fmt.Sprintf("must be between %g and %g", b.min, b.max))
+				}
+				continue
+			}
+			numbers[p.Name] = n
+		}
+
+		switch p.In {
+		case openapi.InPath:
+			bound.Path[p.Name] = s
+		case openapi.InQuery:
+			bound.Query.Set(p.Name, s)
+		case openapi.InForm:
+			bound.Form.Set(p.Name, s)
+		}
+	}
+
+	for _, o := range paramOrder {
+		low, okLow := numbers[o.low]
+		high, okHigh := numbers[o.high]
+		if !okLow || !okHigh {
+			continue
+		}
+		if o.strict && high <= low {
+			verr.Add(o.high, "must be greater than "+o.low)
+		} else if high < low {
+			verr.Add(o.high, "must not be less than "+o.low)
+		}
+	}
+
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	return bound, nil
+}
+
+// bindValue renders val as the request value of p. n is its numeric value
+// for integer and number parameters. problem explains why val does not fit.
+func bindValue(p openapi.Param, val any) (s string, n float64, problem string) {
+	switch p.Type {
+	case "integer":
+		i, ok := integerArg(val, timestampParams[p.Name])
+		if !ok {
+			if timestampParams[p.Name] {
+				return "", 0, "must be a timestamp in milliseconds or an RFC 3339 time"
+			}
+			return "", 0, "must be an integer"
+		}
+		return strconv.FormatInt(i, 10), float64(i), ""
+	case "number":
+		f, ok := numberArg(val)
+		if !ok {
+			return "", 0, "must be a number"
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64), f, ""
+	case "boolean":
+		switch v := val.(type) {
+		case bool:
+			return strconv.FormatBool(v), 0, ""
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return strconv.FormatBool(b), 0, ""
+			}
+		}
+		return "", 0, "must be a boolean"
+	default:
+		s, ok := stringArg(val)
+		if !ok {
+			return "", 0, "must be a string"
+		}
+		return s, 0, ""
+	}
+}
+
+// maxExactInt is the largest integer a JSON number (a float64) holds exactly.
+const maxExactInt = 1 << 53
+
+func integerArg(val any, timestamp bool) (int64, bool) {
+	switch v := val.(type) {
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > maxExactInt {
+			return 0, false
+		}
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case string:
+		v = strings.TrimSpace(v)
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i, true
+		}
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return integerArg(f, false)
+		}
+		if timestamp {
+			for _, layout := range []string{time.RFC3339, time.DateOnly} {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t.UnixMilli(), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func numberArg(val any) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// stringArg accepts a string, a number (a genre id sent as 93) or a list of
+// either, which is joined with commas as the API expects for id lists.
+func stringArg(val any) (string, bool) {
+	switch v := val.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			if _, nested := item.([]any); nested {
+				return "", false
+			}
+			s, ok := stringArg(item)
+			if !ok {
+				return "", false
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), true
+	}
+	return "", false
+}
+
+func inEnum(s string, enum []any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == s {
+			return true
+		}
+	}
+	return false
+}
+
+func joinEnum(enum []any) string {
+	names := make([]string, len(enum))
+	for i, e := range enum {
+		names[i] = fmt.Sprint(e)
+	}
+	return strings.Join(names, ", ")
+}
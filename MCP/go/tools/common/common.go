@@ -0,0 +1,83 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// APIKeyArgument is the optional tool argument that carries a per-call API
+// key when config.APIConfig.AllowAPIKeyArgument is set.
+const APIKeyArgument = "X-ListenAPI-Key"
+
+// WithAPIKeyArgument adds the optional APIKeyArgument to tool and makes its
+// handler send that key upstream instead of the configured one.
+func WithAPIKeyArgument(tool models.Tool) models.Tool {
+	mcp.WithString(APIKeyArgument,
+		mcp.Description("Listen API key to use for this call instead of the server's configured key. Get API Key on listennotes.com/api"),
+	)(&tool.Definition)
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if args, ok := request.Params.Arguments.(map[string]any); ok {
+			if key, ok := args[APIKeyArgument].(string); ok && key != "" {
+				ctx = listenapi.WithAPIKey(ctx, key)
+			}
+		}
+		return handler(ctx, request)
+	}
+	return tool
+}
+
+// Result turns the outcome of a listenapi call into a tool result: the
+// response as structured content, and pretty-printed as text for clients
+// that do not read structured content.
+func Result(result any, err error) (*mcp.CallToolResult, error) {
+	if err != nil {
+		return ErrorResult(err), nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+	}
+	var prettyJSON bytes.Buffer
+	if err := json.Indent(&prettyJSON, data, "", "  "); err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+	}
+
+	// Structured content must be an object matching the tool's output
+	// schema; every Listen API response is, so anything else is a failure
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return mcp.NewToolResultError("Response is not a JSON object: " + prettyJSON.String()), nil
+	}
+	return mcp.NewToolResultStructured(json.RawMessage(data), prettyJSON.String()), nil
+}
+
+// ErrorResult renders a listenapi error the way tools report failures.
+func ErrorResult(err error) *mcp.CallToolResult {
+	var validationErr *ValidationError
+	var apiErr *listenapi.APIError
+	var transportErr *listenapi.TransportError
+	var decodeErr *listenapi.DecodeError
+	switch {
+	case errors.As(err, &validationErr):
+		result := mcp.NewToolResultError(validationErr.Error())
+		result.StructuredContent = validationErr
+		return result
+	case errors.As(err, &apiErr):
+		return mcp.NewToolResultError(apiErr.Error())
+	case errors.As(err, &transportErr):
+		return mcp.NewToolResultErrorFromErr("Request failed", transportErr.Err)
+	case errors.As(err, &decodeErr):
+		// The raw body is all there is to show, and it fits no output schema
+		return mcp.NewToolResultError(decodeErr.Error() + ": " + string(decodeErr.Body))
+	default:
+		return mcp.NewToolResultError(err.Error())
+	}
+}
@@ -0,0 +1,79 @@
+package common
+
+import (
+	"context"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// OperationTool builds the tool for an operation straight from its spec:
+// one argument per path, query and form parameter, an output schema from its
+// response model, and a handler that binds the arguments and makes the
+// single upstream call.
+func OperationTool(client *listenapi.Client, op openapi.Operation) models.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription(op.Summary),
+		mcp.WithRawOutputSchema(OutputSchema(op.Response)),
+	}
+	for _, p := range op.Params {
+		opts = append(opts, ParamOption(p))
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		bound, err := Bind(args, op.Params)
+		if err != nil {
+			return ErrorResult(err), nil
+		}
+		result, err := op.Call(ctx, client, bound.Path, bound.Query, bound.Form)
+		return Result(result, err)
+	}
+
+	return models.Tool{
+		Definition: mcp.NewTool(op.ToolName, opts...),
+		Handler:    handler,
+	}
+}
+
+// ParamOption declares p as a tool argument with the type, enum, default and
+// required flag from the spec.
+func ParamOption(p openapi.Param) mcp.ToolOption {
+	props := []mcp.PropertyOption{mcp.Description(p.Description)}
+	if p.Required {
+		props = append(props, mcp.Required())
+	}
+	if p.Type == "integer" {
+		props = append(props, Integer())
+	}
+	props = append(props, func(schema map[string]any) {
+		if len(p.Enum) > 0 {
+			schema["enum"] = p.Enum
+		}
+		if p.Default != nil {
+			schema["default"] = p.Default
+		}
+	})
+
+	switch p.Type {
+	case "integer", "number":
+		return mcp.WithNumber(p.Name, props...)
+	case "boolean":
+		return mcp.WithBoolean(p.Name, props...)
+	default:
+		return mcp.WithString(p.Name, props...)
+	}
+}
+
+// Integer declares a number argument as a JSON Schema integer, as the spec
+// does for ids, flags and millisecond timestamps.
+func Integer() mcp.PropertyOption {
+	return func(schema map[string]any) {
+		schema["type"] = "integer"
+	}
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// PagesArgument is the optional argument of list tools that fetches several
+// pages in one call.
+const PagesArgument = "pages"
+
+// pagesParam declares PagesArgument; Bind checks it against paramBounds.
+var pagesParam = openapi.Param{
+	Name:        PagesArgument,
+	In:          openapi.InQuery,
+	Type:        "integer",
+	Description: fmt.Sprintf("Number of pages to fetch, from 1 to %d, starting at the page the other arguments select. The pages are fetched one after the other and returned as one, each costing one API request; calls with a progress token get a progress notification per page.", listenapi.MaxPages),
+	Default:     1,
+}
+
+// WithPages adds the optional PagesArgument to tool. When it asks for more
+// than one page, the handler binds the arguments to params and fetches the
+// pages with fetch instead of calling the tool's own handler.
+func WithPages(tool models.Tool, params []openapi.Param, fetch func(ctx context.Context, args *Args, pages int) (any, error)) models.Tool {
+	ParamOption(pagesParam)(&tool.Definition)
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return handler(ctx, request)
+		}
+		bound, err := Bind(args, []openapi.Param{pagesParam})
+		if err != nil {
+			return ErrorResult(err), nil
+		}
+		pages, _ := strconv.Atoi(bound.Query.Get(PagesArgument))
+		if pages <= 1 {
+			return handler(ctx, request)
+		}
+
+		bound, err = Bind(args, params)
+		if err != nil {
+			return ErrorResult(err), nil
+		}
+		result, err := fetch(ctx, bound, pages)
+		return Result(result, err)
+	}
+	return tool
+}
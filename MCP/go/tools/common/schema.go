@@ -0,0 +1,68 @@
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// OutputSchema returns the JSON Schema a tool declares for its structured
+// content, derived from the models type of its response. Results carry the
+// response as the API sent it, so the schema only describes the fields the
+// model knows: none is required, any may be null, and other properties are
+// allowed.
+func OutputSchema(model reflect.Type) json.RawMessage {
+	schema := typeSchema(model, map[reflect.Type]bool{})
+	// The MCP spec wants an object at the root, and never null
+	schema["type"] = "object"
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// typeSchema describes values of t. seen holds the structs being described,
+// so that a model that refers to itself ends in an unconstrained schema.
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]any{}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		props := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = typeSchema(f.Type, seen)
+		}
+		return map[string]any{"type": []string{"object", "null"}, "properties": props}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": []string{"array", "null"}, "items": typeSchema(t.Elem(), seen)}
+	case reflect.String:
+		return map[string]any{"type": []string{"string", "null"}}
+	case reflect.Bool:
+		return map[string]any{"type": []string{"boolean", "null"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": []string{"integer", "null"}}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": []string{"number", "null"}}
+	default:
+		// Maps and interfaces, such as the oneOf fields of a model, hold
+		// anything
+		return map[string]any{}
+	}
+}
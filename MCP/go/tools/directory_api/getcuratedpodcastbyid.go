@@ -1,85 +0,0 @@
-package tools
-
-import (
-	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-
-	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
-	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
-	"github.com/mark3labs/mcp-go/mcp"
-)
-
-func GetcuratedpodcastbyidHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		args, ok := request.Params.Arguments.(map[string]any)
-		if !ok {
-			return mcp.NewToolResultError("Invalid arguments object"), nil
-		}
-		idVal, ok := args["id"]
-		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: id"), nil
-		}
-		id, ok := idVal.(string)
-		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: id"), nil
-		}
-		url := fmt.Sprintf("%s/curated_podcasts/%s", cfg.BaseURL, id)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Handle multiple authentication parameters
-		if cfg.APIKey != "" {
-			req.Header.Set("X-ListenAPI-Key", cfg.APIKey)
-		}
-		req.Header.Set("Accept", "application/json")
-		if val, ok := args["X-ListenAPI-Key"]; ok {
-			req.Header.Set("X-ListenAPI-Key", fmt.Sprintf("%v", val))
-		}
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result models.CuratedListFull
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
-	}
-}
-
-func CreateGetcuratedpodcastbyidTool(cfg *config.APIConfig) models.Tool {
-	tool := mcp.NewTool("get_curated_podcasts_id",
-		mcp.WithDescription("Fetch a curated list of podcasts by id"),
-		mcp.WithString("X-ListenAPI-Key", mcp.Required(), mcp.Description("Get API Key on listennotes.com/api")),
-		mcp.WithString("id", mcp.Required(), mcp.Description("id for a specific curated list of podcasts. You can get the id from the response of `GET /search?type=curated` or `GET /curated_podcasts`.\n")),
-	)
-
-	return models.Tool{
-		Definition: tool,
-		Handler:    GetcuratedpodcastbyidHandler(cfg),
-	}
-}
@@ -1,95 +0,0 @@
-package tools
-
-import (
-	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
-
-	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/config"
-	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
-	"github.com/mark3labs/mcp-go/mcp"
-)
-
-func GetepisodebyidHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		args, ok := request.Params.Arguments.(map[string]any)
-		if !ok {
-			return mcp.NewToolResultError("Invalid arguments object"), nil
-		}
-		idVal, ok := args["id"]
-		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: id"), nil
-		}
-		id, ok := idVal.(string)
-		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: id"), nil
-		}
-		queryParams := make([]string, 0)
-		if val, ok := args["show_transcript"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("show_transcript=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/episodes/%s%s", cfg.BaseURL, id, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Handle multiple authentication parameters
-		if cfg.APIKey != "" {
-			req.Header.Set("X-ListenAPI-Key", cfg.APIKey)
-		}
-		req.Header.Set("Accept", "application/json")
-		if val, ok := args["X-ListenAPI-Key"]; ok {
-			req.Header.Set("X-ListenAPI-Key", fmt.Sprintf("%v", val))
-		}
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result models.EpisodeFull
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
-	}
-}
-
-func CreateGetepisodebyidTool(cfg *config.APIConfig) models.Tool {
-	tool := mcp.NewTool("get_episodes_id",
-		mcp.WithDescription("Fetch detailed meta data for an episode by id"),
-		mcp.WithString("X-ListenAPI-Key", mcp.Required(), mcp.Description("Get API Key on listennotes.com/api")),
-		mcp.WithString("id", mcp.Required(), mcp.Description("id for a specific episode. You can get episode id from using other endpoints, e.g., `GET /search`...")),
-		mcp.WithNumber("show_transcript", mcp.Description("To include the transcript of this episode or not? If it is 1, then include the transcript in the **transcript** field. The default value is 0 - we don't include transcript by default, because 1) it would make the response data very big, thus slow response time; 2) less than 1% of episodes have transcripts. The transcript field is available only in the PRO/ENTERPRISE plan.")),
-	)
-
-	return models.Tool{
-		Definition: tool,
-		Handler:    GetepisodebyidHandler(cfg),
-	}
-}
@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func GetepisodesinbatchHandler(client *listenapi.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	op, _ := openapi.Lookup("getEpisodesInBatch")
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		bound, err := common.Bind(args, op.Params)
+		if err != nil {
+			return common.ErrorResult(err), nil
+		}
+		result, err := client.GetEpisodesBatch(ctx, bound.Form)
+		return common.Result(result, err)
+	}
+}
+
+func CreateGetepisodesinbatchTool(client *listenapi.Client) models.Tool {
+	op, _ := openapi.Lookup("getEpisodesInBatch")
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Batch fetch basic meta data for episodes by id. Any number of episodes may be requested; they are fetched 10 at a time. Available only in the PRO/ENTERPRISE plan."),
+		mcp.WithRawOutputSchema(common.OutputSchema(op.Response)),
+	}
+	opts = append(opts, batchParamOptions(op.Params, "ids")...)
+
+	return models.Tool{
+		Definition: mcp.NewTool("get_episodes_batch", opts...),
+		Handler:    GetepisodesinbatchHandler(client),
+	}
+}
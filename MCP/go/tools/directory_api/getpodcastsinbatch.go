@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func GetpodcastsinbatchHandler(client *listenapi.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	op, _ := openapi.Lookup("getPodcastsInBatch")
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		bound, err := common.Bind(args, op.Params)
+		if err != nil {
+			return common.ErrorResult(err), nil
+		}
+		form := bound.Form
+		if form.Get("ids") == "" && form.Get("rsses") == "" && form.Get("itunes_ids") == "" && form.Get("spotify_ids") == "" {
+			verr := &common.ValidationError{}
+			verr.Add("ids", "is required unless rsses, itunes_ids or spotify_ids is given")
+			return common.ErrorResult(verr), nil
+		}
+		result, err := client.GetPodcastsBatch(ctx, form)
+		return common.Result(result, err)
+	}
+}
+
+// batchParamOptions declares the parameters of a batch operation from the
+// spec, noting on the id lists named by lists that the 10 id limit of a
+// single request does not apply.
+func batchParamOptions(params []openapi.Param, lists ...string) []mcp.ToolOption {
+	var opts []mcp.ToolOption
+	for _, p := range params {
+		if slices.Contains(lists, p.Name) {
+			p.Description = strings.TrimRight(p.Description, ". \n") + ". Any number may be given; they are fetched 10 at a time."
+		}
+		opts = append(opts, common.ParamOption(p))
+	}
+	return opts
+}
+
+func CreateGetpodcastsinbatchTool(client *listenapi.Client) models.Tool {
+	op, _ := openapi.Lookup("getPodcastsInBatch")
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Batch fetch basic meta data for podcasts by podcast id, rss url, Apple Podcasts (iTunes) id or Spotify id. Any number of podcasts may be requested; they are fetched 10 at a time. Available only in the PRO/ENTERPRISE plan."),
+		mcp.WithRawOutputSchema(common.OutputSchema(op.Response)),
+	}
+	opts = append(opts, batchParamOptions(op.Params, "ids", "rsses", "itunes_ids", "spotify_ids")...)
+
+	return models.Tool{
+		Definition: mcp.NewTool("get_podcasts_batch", opts...),
+		Handler:    GetpodcastsinbatchHandler(client),
+	}
+}
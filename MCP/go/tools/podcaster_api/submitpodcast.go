@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"net/mail"
+	"net/url"
+
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/listenapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/models"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/openapi"
+	"github.com/listen-api-podcast-search-directory-and-insights-api/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func SubmitpodcastHandler(client *listenapi.Client) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	op, _ := openapi.Lookup("submitPodcast")
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		bound, err := common.Bind(args, op.Params)
+		if err != nil {
+			return common.ErrorResult(err), nil
+		}
+		verr := &common.ValidationError{}
+		if !validFeedURL(bound.Form.Get("rss")) {
+			verr.Add("rss", "must be an absolute http or https url")
+		}
+		if email := bound.Form.Get("email"); email != "" {
+			if _, err := mail.ParseAddress(email); err != nil {
+				verr.Add("email", "must be a valid email address")
+			}
+		}
+		if err := verr.Err(); err != nil {
+			return common.ErrorResult(err), nil
+		}
+		result, err := client.SubmitPodcast(ctx, bound.Form)
+		return common.Result(result, err)
+	}
+}
+
+// validFeedURL reports whether s looks like an RSS feed url the API could
+// fetch: absolute, http or https, and with a host.
+func validFeedURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func CreateSubmitpodcastTool(client *listenapi.Client) models.Tool {
+	op, _ := openapi.Lookup("submitPodcast")
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Submit a podcast rss url to the Listen Notes database. The response status is \"found\" if the podcast already exists, \"in review\" if it is new and will be reviewed within 12 hours, or \"rejected\"."),
+		mcp.WithRawOutputSchema(common.OutputSchema(op.Response)),
+	}
+	for _, p := range op.Params {
+		opts = append(opts, common.ParamOption(p))
+	}
+
+	return models.Tool{
+		Definition: mcp.NewTool("submit_podcast", opts...),
+		Handler:    SubmitpodcastHandler(client),
+	}
+}